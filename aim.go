@@ -0,0 +1,51 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// playerAimToggle makes the right mouse button switch aiming on and off
+// instead of having to hold it down
+var playerAimToggle bool = false
+
+// How aiming down the sights changes the player's movement and shooting
+var (
+	playerAimSpeedFactor  float64 = 0.5 // Amount to change speed by while aiming
+	playerAimRangeFactor  float64 = 1.5 // Amount to change the range of the gun by
+	playerAimSpreadFactor float64 = 0.4 // Amount to change the spread of the gun by
+)
+
+// handleAiming lets the player aim down the sights with the right mouse
+// button, either while holding it or toggled by clicking it
+func (g *GameScreen) handleAiming() {
+	p := g.Player
+	switch {
+	case p.Staggered > 0 || p.State == playerReload:
+		p.Aiming = false
+	case playerAimToggle:
+		if clickedRight() {
+			p.Aiming = !p.Aiming
+		}
+	default:
+		p.Aiming = ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight)
+	}
+	g.Zoom.On = p.Aiming
+}
+
+// Range returns how far the player's bullets fly, further while aiming
+func (p *Player) Range() float64 {
+	if p.Aiming {
+		return p.Gun().Range * playerAimRangeFactor
+	}
+	return p.Gun().Range
+}
+
+// spreadFactor is how much the spread of the gun is changed by, it is
+// tighter while aiming
+func (p *Player) spreadFactor() float64 {
+	if p.Aiming {
+		return playerAimSpreadFactor
+	}
+	return 1
+}
@@ -0,0 +1,268 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"sort"
+)
+
+// Names of the sounds a zombie archetype can make
+const (
+	zombieSoundDetect = "detect" // Played when the zombie notices its target
+	zombieSoundHurt   = "hurt"   // Played when the zombie is shot but survives
+	zombieSoundDeath  = "death"  // Played when the zombie dies
+)
+
+// FloatRange is a range of numbers given as [min, max]
+type FloatRange [2]float64
+
+// Random returns a random number in the range
+func (r FloatRange) Random() float64 {
+	return r[0] + rand.Float64()*(r[1]-r[0])
+}
+
+// IntRange is a range of whole numbers given as [min, max], both inclusive
+type IntRange [2]int
+
+// Random returns a random whole number in the range
+func (r IntRange) Random() int {
+	if r[1] <= r[0] {
+		return r[0]
+	}
+	return r[0] + rand.Intn(r[1]-r[0]+1)
+}
+
+// SoundFile refers to a sound in the assets by file name without the
+// extension, and how many numbered variants of it there are
+type SoundFile struct {
+	File     string `json:"file"`
+	Variants int    `json:"variants"`
+}
+
+// ZombieArchetype describes one type of zombie, it is loaded from the zombie
+// definitions file so new zombies can be added without changing the code
+type ZombieArchetype struct {
+	Name         string               `json:"-"`            // Name of the archetype, the key in the definitions file
+	SpriteNames  []string             `json:"sprites"`      // Sprite sheets, one is picked randomly for each zombie
+	Speed        FloatRange           `json:"speed"`        // Distance the zombie moves per update cycle
+	HitPoints    IntRange             `json:"hitPoints"`    // Number of hits needed to die
+	Range        float64              `json:"range"`        // How far away the zombie sees something to attack
+	CollisionBox float64              `json:"collisionBox"` // Size of the collision box around the zombie's head
+	Damage       int                  `json:"damage"`       // How much health an attack takes away
+	Mass         float64              `json:"mass"`         // How hard the zombie is to push around, defaults to 1
+	Pressure     FloatRange           `json:"pressure"`     // Spawn weight multiplier under the least and the most pressure
+	Boss         *BossDefinition      `json:"boss"`         // Boss fight definition if the zombie is a boss
+	SoundFiles   map[string]SoundFile `json:"sounds"`       // Sounds the zombie makes, by name
+	Sprites      []*SpriteSheet       `json:"-"`            // Loaded sprite sheets
+	Sounds       map[string]*Sound    `json:"-"`            // Loaded sounds
+}
+
+// RandomSprite picks one of the archetype's sprite sheets
+func (a *ZombieArchetype) RandomSprite() *SpriteSheet {
+	return a.Sprites[rand.Intn(len(a.Sprites))]
+}
+
+// PlaySound plays one of the archetype's sounds if it has one with that name
+func (a *ZombieArchetype) PlaySound(name string) {
+	if sound, ok := a.Sounds[name]; ok {
+		sound.Play()
+	}
+}
+
+// SpawnChance is how likely a spawner is to spawn a zombie archetype,
+// relative to the weights of the other archetypes it can spawn
+type SpawnChance struct {
+	ArchetypeName string           `json:"archetype"`
+	Weight        float64          `json:"weight"`
+	Archetype     *ZombieArchetype `json:"-"`
+}
+
+// SpawnMix is the set of archetypes a spawner can spawn
+type SpawnMix []SpawnChance
+
+// Pick chooses a random archetype from the mix according to the weights
+func (m SpawnMix) Pick() *ZombieArchetype {
+	return m.PickWith(func(c SpawnChance) float64 { return c.Weight })
+}
+
+// PickWith chooses a random archetype from the mix according to the weights
+// returned by the weight function
+func (m SpawnMix) PickWith(weight func(SpawnChance) float64) *ZombieArchetype {
+	total := 0.0
+	for _, c := range m {
+		total += weight(c)
+	}
+	r := rand.Float64() * total
+	for _, c := range m {
+		r -= weight(c)
+		if r < 0 {
+			return c.Archetype
+		}
+	}
+	return m[len(m)-1].Archetype
+}
+
+// ZombieDefinitions contains all the zombie archetypes and which of them each
+// type of spawner entity on the map spawns
+type ZombieDefinitions struct {
+	Archetypes map[string]*ZombieArchetype `json:"archetypes"`
+	Spawners   map[string]SpawnMix         `json:"spawners"`
+}
+
+// Load the zombie definitions file from the embedded FS along with all the
+// sprites and sounds its archetypes use
+func loadZombieDefinitions(name string) *ZombieDefinitions {
+	log.Printf("loading %s\n", name)
+
+	file, err := assets.Open(name)
+	if err != nil {
+		log.Fatalf("error opening file %s: %v\n", name, err)
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		log.Fatalf("error reading from file %s: %v\n", name, err)
+	}
+
+	var defs ZombieDefinitions
+	if err := json.Unmarshal(data, &defs); err != nil {
+		log.Fatalf("error parsing file %s as zombie definitions: %v\n", name, err)
+	}
+
+	// Several archetypes can share the same sprites and sounds
+	sprites := map[string]*SpriteSheet{}
+	sounds := map[SoundFile]*Sound{}
+
+	// Load in a fixed order so the loading log is the same every time
+	names := make([]string, 0, len(defs.Archetypes))
+	for name := range defs.Archetypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		a := defs.Archetypes[name]
+		a.Name = name
+		if len(a.SpriteNames) == 0 {
+			log.Fatalf("zombie archetype %s has no sprites\n", name)
+		}
+		if a.Range == 0 {
+			a.Range = zombieRange
+		}
+		if a.Damage == 0 {
+			a.Damage = zombieDamage
+		}
+		if a.Mass == 0 {
+			a.Mass = 1
+		}
+		if a.Pressure == (FloatRange{}) {
+			a.Pressure = FloatRange{1, 1}
+		}
+		for _, s := range a.SpriteNames {
+			if _, ok := sprites[s]; !ok {
+				sprites[s] = loadSprite(s)
+			}
+			a.Sprites = append(a.Sprites, sprites[s])
+		}
+		a.Sounds = make(map[string]*Sound, len(a.SoundFiles))
+		for key, f := range a.SoundFiles {
+			if _, ok := sounds[f]; !ok {
+				sound := &Sound{Volume: 0.7}
+				if f.Variants > 1 {
+					sound.AddSound(f.File, sampleRate, context, f.Variants)
+				} else {
+					sound.AddSound(f.File, sampleRate, context)
+				}
+				sounds[f] = sound
+			}
+			a.Sounds[key] = sounds[f]
+		}
+	}
+
+	for _, name := range names {
+		if a := defs.Archetypes[name]; a.Boss != nil {
+			validateBoss(a, defs.Archetypes)
+		}
+	}
+
+	for spawner, mix := range defs.Spawners {
+		if len(mix) == 0 {
+			log.Fatalf("zombie spawner %s has no archetypes\n", spawner)
+		}
+		for i, c := range mix {
+			a, ok := defs.Archetypes[c.ArchetypeName]
+			if !ok {
+				log.Fatalf("zombie spawner %s uses unknown archetype %s\n", spawner, c.ArchetypeName)
+			}
+			mix[i].Archetype = a
+		}
+	}
+
+	return &defs
+}
+
+// validateBoss checks that a boss definition can be used with its archetype's
+// sprites and finds the archetypes its attacks summon
+func validateBoss(a *ZombieArchetype, archetypes map[string]*ZombieArchetype) {
+	b := a.Boss
+	if len(b.Phases) == 0 {
+		log.Fatalf("zombie boss %s has no phases\n", a.Name)
+	}
+	if b.DefeatedAt < 1 {
+		b.DefeatedAt = 1
+	}
+
+	var animations []string
+	for _, p := range b.Phases {
+		animations = append(animations, p.Idle, p.Walking)
+		if p.Hit != "" {
+			animations = append(animations, p.Hit)
+		}
+		for _, step := range p.Transition {
+			animations = append(animations, step.Animation)
+		}
+	}
+	for _, step := range b.Death {
+		animations = append(animations, step.Animation)
+	}
+	for _, p := range b.Phases {
+		for _, name := range p.Attacks {
+			if _, ok := b.Attacks[name]; !ok {
+				log.Fatalf("zombie boss %s has no attack called %s\n", a.Name, name)
+			}
+		}
+	}
+	for name, attack := range b.Attacks {
+		switch attack.Kind {
+		case bossAttackCharge, bossAttackSlam:
+		case bossAttackSummon:
+			summoned, ok := archetypes[attack.Summons]
+			if !ok {
+				log.Fatalf("zombie boss %s attack %s summons unknown archetype %s\n", a.Name, name, attack.Summons)
+			}
+			if summoned.Boss != nil {
+				log.Fatalf("zombie boss %s attack %s can't summon another boss\n", a.Name, name)
+			}
+			attack.Archetype = summoned
+		default:
+			log.Fatalf("zombie boss %s attack %s has unknown kind %s\n", a.Name, name, attack.Kind)
+		}
+		if attack.Telegraph < 1 {
+			attack.Telegraph = 1
+		}
+	}
+
+	for _, sprite := range a.Sprites {
+		for _, name := range animations {
+			if _, ok := sprite.FrameTag(name); !ok {
+				log.Fatalf("zombie boss %s has no animation called %s\n", a.Name, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestRangeRandom(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if got := (IntRange{1, 2}).Random(); got < 1 || got > 2 {
+			t.Errorf("IntRange{1, 2} gave %d, want 1 or 2", got)
+		}
+		if got := (IntRange{10, 10}).Random(); got != 10 {
+			t.Errorf("IntRange{10, 10} gave %d, want 10", got)
+		}
+		if got := (FloatRange{0.4, 0.8}).Random(); got < 0.4 || got > 0.8 {
+			t.Errorf("FloatRange{0.4, 0.8} gave %f, want between 0.4 and 0.8", got)
+		}
+	}
+}
+
+func TestSpawnMixPick(t *testing.T) {
+	normal := &ZombieArchetype{Name: "normal"}
+	never := &ZombieArchetype{Name: "never"}
+	mix := SpawnMix{
+		{Weight: 0, Archetype: never},
+		{Weight: 1, Archetype: normal},
+	}
+	for i := 0; i < 100; i++ {
+		if got := mix.Pick(); got != normal {
+			t.Errorf("Picked %s from mix, want %s because the other weight is 0", got.Name, normal.Name)
+		}
+	}
+}
@@ -1,3 +1,6 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
 package main
 
 import (
@@ -5,16 +8,87 @@ import (
 	"log"
 )
 
-//go:generate ./tools/gen_sprite_tags.sh assets/sprites/Zombie_big.json boss_anim.go boss
+// BossStep is one animation in a sequence the boss plays while standing still,
+// e.g. when it transforms into its next phase or dies
+type BossStep struct {
+	Animation  string `json:"animation"`  // Name of the animation in the sprite sheet
+	StartSound string `json:"startSound"` // Archetype sound played when the animation starts
+	EndSound   string `json:"endSound"`   // Archetype sound played when the animation ends
+}
+
+// BossPhase is one stage of a boss fight, it starts once the boss is hurt down
+// to its hit points and changes how the boss looks and moves
+type BossPhase struct {
+	HitPoints  int        `json:"hitPoints"`  // The phase starts at this many hit points
+	Idle       string     `json:"idle"`       // Animation when the boss has no target
+	Walking    string     `json:"walking"`    // Animation when the boss is moving
+	Hit        string     `json:"hit"`        // Animation when the boss is shot, defaults to Walking
+	Speed      float64    `json:"speed"`      // Speed in this phase, 0 keeps the current speed
+	Transition []BossStep `json:"transition"` // Sequence played before the phase starts
+	Attacks    []string   `json:"attacks"`    // Names of the attacks used in this phase, tried in order
+}
+
+// BossArena is the area around the boss's spawn point which is locked while
+// the boss is being fought
+type BossArena struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// BossDefinition describes the phases of a boss fight and its encounter
+type BossDefinition struct {
+	Phases     []BossPhase            `json:"phases"`     // Phases from first to last
+	DefeatedAt int                    `json:"defeatedAt"` // The boss is defeated at this many hit points
+	Death      []BossStep             `json:"death"`      // Sequence played before the boss dies
+	Arena      BossArena              `json:"arena"`      // Area locked during the fight
+	Attacks    map[string]*BossAttack `json:"attacks"`    // Attacks the phases can use, by name
+}
+
+// phaseAt returns the index of the phase the boss is in at the given hit points
+func (d *BossDefinition) phaseAt(hitPoints int) int {
+	phase := 0
+	for i, p := range d.Phases {
+		if hitPoints <= p.HitPoints {
+			phase = i
+		}
+	}
+	return phase
+}
 
-// Boss zombie, it is bigger than the rest and you need to kill it twice
+// Boss zombie, it is bigger than the rest and it goes through several phases
+// defined by its archetype before it dies
 type Boss struct {
-	*Zombie                   // Inner zombie behaviour
-	Daemon  bool              // Whether it has respawned into daemon mode aka Phase 2
-	State   bossAnimationTags // Boss animation state
-	Frame   int               // Current animation frame
-	Dying   bool              // Whether the death and respawn animation is still playing
-	Dead    bool              // Whether the boss has reached its final death
+	*Zombie                             // Inner zombie behaviour
+	Definition      *BossDefinition     // Phases and encounter of this boss
+	Phase           int                 // Index of the current phase
+	NextPhase       int                 // Phase which starts when the sequence ends
+	Animation       FrameTags           // Current animation
+	Frame           int                 // Current animation frame
+	Sequence        []BossStep          // Animations left to play before the boss moves again
+	Dying           bool                // Whether the sequence being played is the death
+	Dead            bool                // Whether the boss has reached its final death
+	MaxHitPoints    int                 // Hit points the boss started with
+	Attack          *BossAttack         // Attack in progress, if any
+	AttackStage     int                 // Stage of the attack in progress
+	AttackTime      int                 // Ticks spent in the current stage of the attack
+	AttackDirection Coord               // Direction the attack is aimed in
+	Recovery        int                 // Ticks left until the boss can attack again
+	Cooldowns       map[*BossAttack]int // Ticks left until each attack can be used again
+}
+
+// NewBoss wraps a zombie with the boss behaviour defined by its archetype
+func NewBoss(z *Zombie) *Boss {
+	boss := &Boss{
+		Zombie:       z,
+		Definition:   z.Archetype.Boss,
+		MaxHitPoints: z.HitToDie,
+		Cooldowns:    map[*BossAttack]int{},
+	}
+	boss.Phase = boss.Definition.phaseAt(z.HitToDie)
+	boss.NextPhase = boss.Phase
+	boss.Animation = boss.animation(boss.Definition.Phases[boss.Phase].Idle)
+	z.Object.Data = boss
+	return boss
 }
 
 // Update boss-specific zombie behaviour
@@ -23,19 +97,39 @@ func (z *Boss) Update(g *GameScreen) error {
 		return errors.New("Zombie Boss died")
 	}
 
-	if !z.Dying {
+	if len(z.Sequence) == 0 {
 		z.hitpointBasedStateChanges(g)
 	}
+	for a, ticks := range z.Cooldowns {
+		if ticks > 0 {
+			z.Cooldowns[a] = ticks - 1
+		}
+	}
+
+	if len(z.Sequence) == 0 && z.Attack == nil && z.Zombie.State != zombieStunned {
+		z.startAttack(g)
+	}
+
+	switch {
+	case len(z.Sequence) > 0:
+		// the sequence sets its own animations
+	case z.Attack != nil:
+		z.Animation = z.attackAnimation()
+	default:
+		z.Animation = z.phaseAnimation()
+	}
 
-	z.Frame = Animate(z.Frame, g.Tick, z.Sprite.Meta.FrameTags[z.State])
-	if z.Frame == z.Sprite.Meta.FrameTags[z.State].To {
+	z.Frame = Animate(z.Frame, g.Tick, z.Animation)
+	if z.Frame == z.Animation.To {
 		z.animationEndTriggers(g)
 	}
-	if z.Frame == z.Sprite.Meta.FrameTags[z.State].From {
-		z.animationStartTriggers(g)
+
+	if len(z.Sequence) > 0 || z.Dead {
+		return nil // stand still while transforming or dying
 	}
 
-	if z.State == bossDeath1 || z.State == bossDeath2 || z.State == bossPhase2 {
+	if z.Attack != nil {
+		z.updateAttack(g)
 		return nil
 	}
 
@@ -45,124 +139,143 @@ func (z *Boss) Update(g *GameScreen) error {
 
 // Draw draws the Zombie to the screen
 func (z *Boss) Draw(g *GameScreen) {
+	z.drawTelegraph(g)
+
+	// Turn red while warning of an attack like a zombie winding up
+	if z.Attack != nil && z.AttackStage == bossAttackTelegraph {
+		z.Zombie.Windup = z.AttackTime * zombieAttackWindup / z.Attack.Telegraph
+	}
+
 	z.Zombie.Frame = z.Frame
 	z.Zombie.Draw(g)
 }
 
+// Hit hurts the boss, unless it is in the middle of a sequence
+func (z *Boss) Hit(g *GameScreen, damage int) {
+	if len(z.Sequence) > 0 || z.Dead {
+		return
+	}
+	z.Zombie.Hit(g, damage)
+}
+
+// Knock only pushes the boss around while it is walking, it stands its
+// ground while attacking or transforming
+func (z *Boss) Knock(g *GameScreen, push Coord, stagger int) {
+	if len(z.Sequence) > 0 || z.Dead || z.Attack != nil {
+		return
+	}
+	z.Zombie.Knock(g, push, stagger)
+}
+
+// Health returns how much of the boss's health is left, from 0 to 1
+func (z *Boss) Health() float64 {
+	total := z.MaxHitPoints - z.Definition.DefeatedAt
+	if total <= 0 {
+		return 0
+	}
+	return float64(z.HitToDie-z.Definition.DefeatedAt) / float64(total)
+}
+
+// animation finds an animation of the boss's sprite by name
+func (z *Boss) animation(name string) FrameTags {
+	tag, _ := z.Sprite.FrameTag(name)
+	return tag
+}
+
+// phaseAnimation returns the animation of the current phase that matches what
+// the inner zombie is doing
+func (z *Boss) phaseAnimation() FrameTags {
+	phase := z.Definition.Phases[z.Phase]
+	switch z.Zombie.State {
+	case zombieIdle:
+		return z.animation(phase.Idle)
+	case zombieHit, zombieStunned:
+		if phase.Hit != "" {
+			return z.animation(phase.Hit)
+		}
+	}
+	return z.animation(phase.Walking)
+}
+
+// startSequence starts playing a sequence of animations
+func (z *Boss) startSequence(g *GameScreen, steps []BossStep) {
+	z.Sequence = steps
+	z.Zombie.Windup = 0
+	z.cancelAttack()
+	if len(z.Sequence) > 0 {
+		z.Animation = z.animation(z.Sequence[0].Animation)
+		z.Frame = z.Animation.From
+		z.Archetype.PlaySound(z.Sequence[0].StartSound)
+	}
+}
+
 // State changes triggered by LAST frame of animation
 func (z *Boss) animationEndTriggers(g *GameScreen) {
-	switch z.State {
-	case bossHit1:
-		z.State = bossWalking1
-		z.Zombie.State = zombieWalking
-	case bossHit2:
-		z.State = bossWalking2
-		z.Zombie.State = zombieWalking
-	case bossDeath1:
-		z.Daemon = true
-		z.Speed = zombieSprinterSpeed * 2
-		z.State = bossPhase2
-		z.Zombie.State = zombieWalking
-	case bossPhase2:
-		z.Dying = false
-		z.State = bossRunning
-		z.Zombie.State = zombieWalking
-		g.Sounds[soundBigZombieScream].Play()
-	case bossDeath2:
+	if len(z.Sequence) == 0 {
+		if z.Zombie.State == zombieHit {
+			z.Zombie.State = zombieWalking
+			if z.Stunned > 0 {
+				z.Zombie.State = zombieStunned // still staggering from the hit
+			}
+		}
+		return
+	}
+
+	z.Archetype.PlaySound(z.Sequence[0].EndSound)
+	z.startSequence(g, z.Sequence[1:])
+	if len(z.Sequence) > 0 {
+		return
+	}
+
+	// The sequence is over
+	if z.Dying {
 		z.Die(g)
-		z.Zombie.State = zombieDead
+		return
 	}
+	z.enterPhase(z.NextPhase)
 }
 
-// State changes triggered by FIRST frame of animation
-func (z *Boss) animationStartTriggers(g *GameScreen) {
-	switch z.State {
-	case bossDeath1:
-		g.Sounds[soundBigZombieDeath1].Play()
-	case bossDeath2:
-		g.Sounds[soundBigZombieDeath2].Play()
+// enterPhase switches to a phase and applies its changes
+func (z *Boss) enterPhase(phase int) {
+	z.Phase = phase
+	if speed := z.Definition.Phases[phase].Speed; speed > 0 {
+		z.Speed = speed
 	}
+	z.Zombie.State = zombieWalking
 }
 
+// hitpointBasedStateChanges starts the next phase or the death of the boss
+// when it has been hurt enough
 func (z *Boss) hitpointBasedStateChanges(g *GameScreen) {
-	if z.Daemon {
-		switch z.HitToDie {
-		case 2:
-			switch z.Zombie.State {
-			case zombieHit:
-				z.State = bossDeath1
-			case zombieIdle:
-				z.State = bossIdle4
-			case zombieWalking:
-				z.State = bossRunning
-			}
-		case 1:
-			switch z.Zombie.State {
-			case zombieHit:
-				z.State = bossDeath2
-			}
-		}
-	} else {
-		switch z.HitToDie {
-		case 10, 9, 8:
-			switch z.Zombie.State {
-			case zombieIdle:
-				z.State = bossIdle1
-			case zombieWalking:
-				z.State = bossWalking1
-			case zombieHit:
-				z.State = bossHit1
-			}
-		case 7: // transition to bleeding arm
-			switch z.Zombie.State {
-			case zombieHit:
-				z.State = bossHit1
-			case zombieIdle:
-				z.State = bossIdle2
-			case zombieWalking:
-				z.State = bossWalking2
-			}
-		case 6, 5:
-			switch z.Zombie.State {
-			case zombieHit:
-				z.State = bossHit2
-			case zombieIdle:
-				z.State = bossIdle2
-			case zombieWalking:
-				z.State = bossWalking2
-			}
-		case 4, 3: // transition to 2 bleeding arms
-			switch z.Zombie.State {
-			case zombieHit:
-				z.State = bossHit2
-			case zombieIdle:
-				z.State = bossIdle3
-			case zombieWalking:
-				z.State = bossWalking3
-			}
-		case 2: // transition to red daemon
-			switch z.Zombie.State {
-			case zombieHit:
-				z.Dying = true
-				z.State = bossDeath1
-			case zombieIdle:
-				z.State = bossIdle4
-			case zombieWalking:
-				z.State = bossRunning
-			}
-		case 1:
-			switch z.Zombie.State {
-			case zombieHit:
-				z.State = bossDeath2
-			}
+	if z.HitToDie <= z.Definition.DefeatedAt {
+		z.Dying = true
+		z.startSequence(g, z.Definition.Death)
+		if len(z.Sequence) == 0 {
+			z.Die(g)
 		}
+		return
+	}
+
+	phase := z.Definition.phaseAt(z.HitToDie)
+	if phase == z.Phase {
+		return
+	}
+	z.NextPhase = phase
+	z.startSequence(g, z.Definition.Phases[phase].Transition)
+	if len(z.Sequence) == 0 {
+		z.enterPhase(phase)
 	}
 }
 
+// Remove the boss from the collision space and its spawn point
 func (z *Boss) Remove() {
+	if z.Object.Space != nil {
+		z.Object.Space.Remove(z.Object)
+	}
 	z.SpawnPoint.RemoveZombie(z)
 }
 
+// Die marks the boss as defeated
 func (z *Boss) Die(g *GameScreen) {
 	log.Println("Boss defeated!")
 	z.Dead = true
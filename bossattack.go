@@ -0,0 +1,210 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Kinds of attack a boss can make
+const (
+	bossAttackCharge = "charge" // Runs across the arena in a straight line
+	bossAttackSlam   = "slam"   // Hits the ground, hurting and staggering the player nearby
+	bossAttackSummon = "summon" // Calls zombies out of the spawn points nearby
+)
+
+// Stages of an attack
+const (
+	bossAttackTelegraph = iota // Standing still, showing what is coming
+	bossAttackActive           // The attack is happening
+)
+
+// bossAttackRecovery is how long (ticks) the boss walks normally between attacks
+const bossAttackRecovery = 90
+
+// bossChargeHitRange is how close the player has to be to a charging boss to get hit
+const bossChargeHitRange = 20
+
+// bossTelegraphColour is the colour of the warning shown before an attack
+var bossTelegraphColour = color.RGBA{0xc8, 0x44, 0x13, 0xa0}
+
+// BossAttack is an attack a boss can make in some of its phases. The boss
+// stands still and shows a warning for the telegraph time before attacking.
+type BossAttack struct {
+	Kind      string           `json:"kind"`      // What kind of attack it is
+	Range     FloatRange       `json:"range"`     // Distance to the player the attack can be started from
+	Telegraph int              `json:"telegraph"` // How long (ticks) the warning lasts
+	Cooldown  int              `json:"cooldown"`  // How long (ticks) until the attack can be used again
+	Sound     string           `json:"sound"`     // Archetype sound played when the warning starts
+	Damage    int              `json:"damage"`    // Damage done to the player, for charge and slam
+	Speed     float64          `json:"speed"`     // Speed of a charge
+	Duration  int              `json:"duration"`  // Longest time (ticks) a charge lasts
+	Radius    float64          `json:"radius"`    // Reach of a slam, or how far away spawn points are summoned from
+	Stagger   int              `json:"stagger"`   // How long (ticks) a slam stops the player from moving
+	Summons   string           `json:"summons"`   // Archetype called by a summon
+	Count     int              `json:"count"`     // How many zombies a summon calls
+	Archetype *ZombieArchetype `json:"-"`         // Loaded archetype called by a summon
+}
+
+// startAttack picks an attack of the current phase that can reach the
+// player and starts its warning, it returns whether an attack was started
+func (z *Boss) startAttack(g *GameScreen) bool {
+	if z.Recovery > 0 {
+		z.Recovery--
+		return false
+	}
+	if z.Zombie.State == zombieIdle || z.Zombie.Target != g.Player.Object {
+		return false
+	}
+
+	distance := CalcDistance(z.Object.X, z.Object.Y, g.Player.Object.X, g.Player.Object.Y)
+	for _, name := range z.Definition.Phases[z.Phase].Attacks {
+		attack := z.Definition.Attacks[name]
+		if z.Cooldowns[attack] > 0 || distance < attack.Range[0] || distance > attack.Range[1] {
+			continue
+		}
+
+		z.Attack = attack
+		z.AttackStage = bossAttackTelegraph
+		z.AttackTime = 0
+		z.AttackDirection = safeNormalize(Coord{
+			X: g.Player.Object.X - z.Object.X,
+			Y: g.Player.Object.Y - z.Object.Y,
+		})
+		z.Angle = math.Atan2(z.AttackDirection.Y, z.AttackDirection.X)
+		z.Zombie.Windup = 0
+		z.Archetype.PlaySound(attack.Sound)
+		return true
+	}
+	return false
+}
+
+// updateAttack carries on with the attack in progress
+func (z *Boss) updateAttack(g *GameScreen) {
+	a := z.Attack
+	z.AttackTime++
+
+	if z.AttackStage == bossAttackTelegraph {
+		// Keep facing the player so they can see where a charge is going
+		if a.Kind == bossAttackCharge {
+			z.AttackDirection = safeNormalize(Coord{
+				X: g.Player.Object.X - z.Object.X,
+				Y: g.Player.Object.Y - z.Object.Y,
+			})
+			z.Angle = math.Atan2(z.AttackDirection.Y, z.AttackDirection.X)
+		}
+		if z.AttackTime < a.Telegraph {
+			return
+		}
+		z.AttackStage = bossAttackActive
+		z.AttackTime = 0
+		z.Zombie.Windup = 0
+	}
+
+	switch a.Kind {
+	case bossAttackCharge:
+		x, y := z.Object.X, z.Object.Y
+		z.move(z.AttackDirection.X*a.Speed, z.AttackDirection.Y*a.Speed)
+		z.Object.Update()
+		if CalcDistance(z.Object.X, z.Object.Y, g.Player.Object.X, g.Player.Object.Y) < bossChargeHitRange {
+			g.Player.Hurt(g, a.Damage, z.Position())
+		}
+		// The charge is over when it runs out of time or into a wall
+		if z.AttackTime < a.Duration && (z.Object.X != x || z.Object.Y != y) {
+			return
+		}
+	case bossAttackSlam:
+		if CalcDistance(z.Object.X, z.Object.Y, g.Player.Object.X, g.Player.Object.Y) < a.Radius {
+			g.Player.Hurt(g, a.Damage, z.Position())
+			g.Player.Stagger(a.Stagger)
+		}
+	case bossAttackSummon:
+		z.summon(g, a)
+	}
+
+	z.Cooldowns[a] = a.Cooldown
+	z.Attack = nil
+	z.Recovery = bossAttackRecovery
+}
+
+// cancelAttack stops the attack in progress without finishing it
+func (z *Boss) cancelAttack() {
+	if z.Attack != nil {
+		z.Cooldowns[z.Attack] = z.Attack.Cooldown
+		z.Attack = nil
+	}
+}
+
+// summon calls zombies out of the spawn points near the boss, except for the
+// boss's own spawn point
+func (z *Boss) summon(g *GameScreen, a *BossAttack) {
+	var near []*SpawnPoint
+	for _, s := range g.SpawnPoints {
+		if s == z.SpawnPoint {
+			continue
+		}
+		if CalcDistance(s.Position.X, s.Position.Y, z.Object.X, z.Object.Y) < a.Radius {
+			near = append(near, s)
+		}
+	}
+	if len(near) == 0 {
+		return
+	}
+	for i := 0; i < a.Count; i++ {
+		near[i%len(near)].Spawn(g, a.Archetype)
+	}
+}
+
+// attackAnimation returns the animation of the current phase that matches
+// the stage of the attack in progress
+func (z *Boss) attackAnimation() FrameTags {
+	phase := z.Definition.Phases[z.Phase]
+	if z.AttackStage == bossAttackTelegraph {
+		return z.animation(phase.Idle)
+	}
+	return z.animation(phase.Walking)
+}
+
+// drawTelegraph shows the player what attack is coming and where
+func (z *Boss) drawTelegraph(g *GameScreen) {
+	if z.Attack == nil || z.AttackStage != bossAttackTelegraph {
+		return
+	}
+	a := z.Attack
+	x, y := g.surfaceCoords(z.Object.X, z.Object.Y)
+
+	switch a.Kind {
+	case bossAttackCharge:
+		length := a.Speed * float64(a.Duration)
+		ebitenutil.DrawLine(
+			g.Camera.Surface,
+			x, y,
+			x+z.AttackDirection.X*length, y+z.AttackDirection.Y*length,
+			bossTelegraphColour,
+		)
+	case bossAttackSlam:
+		// The inner ring grows to the edge as the slam gets closer
+		radius := a.Radius * float64(z.AttackTime) / float64(a.Telegraph)
+		drawRing(g, x, y, a.Radius, bossTelegraphColour)
+		drawRing(g, x, y, radius, bossTelegraphColour)
+	}
+}
+
+// drawRing draws the outline of a circle on the camera surface
+func drawRing(g *GameScreen, x, y, radius float64, clr color.Color) {
+	const segments = 24
+	for i := 0; i < segments; i++ {
+		a1 := 2 * math.Pi * float64(i) / segments
+		a2 := 2 * math.Pi * float64(i+1) / segments
+		ebitenutil.DrawLine(
+			g.Camera.Surface,
+			x+math.Cos(a1)*radius, y+math.Sin(a1)*radius,
+			x+math.Cos(a2)*radius, y+math.Sin(a2)*radius,
+			clr,
+		)
+	}
+}
@@ -42,19 +42,135 @@ func ApplyConfigs() {
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
-	playerAmmoClipMax, err = cfg.Section("Player").Key("PlayerAmmoClipMax").Int()
+	playerGunNoiseRadius, err = cfg.Section("Player").Key("PlayerGunNoiseRadius").Float64()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
-	zombieSpeed, err = cfg.Section("Zombie").Key("ZombieSpeed").Float64()
+	playerMaxHealth, err = cfg.Section("Player").Key("PlayerMaxHealth").Int()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
-	zombieCrawlerSpeed, err = cfg.Section("Zombie").Key("ZombieCrawlerSpeed").Float64()
+	playerInvulnerableTime, err = cfg.Section("Player").Key("PlayerInvulnerableTime").Int()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
-	zombieSprinterSpeed, err = cfg.Section("Zombie").Key("ZombieSprinterSpeed").Float64()
+	playerKnockbackSpeed, err = cfg.Section("Player").Key("PlayerKnockbackSpeed").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerFriendlyFire, err = cfg.Section("Player").Key("PlayerFriendlyFire").Bool()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerFriendlyFireDamage, err = cfg.Section("Player").Key("PlayerFriendlyFireDamage").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerStaminaDrain, err = cfg.Section("Player").Key("PlayerStaminaDrain").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerStaminaRegen, err = cfg.Section("Player").Key("PlayerStaminaRegen").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerStaminaRecovery, err = cfg.Section("Player").Key("PlayerStaminaRecovery").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerWindedSpread, err = cfg.Section("Player").Key("PlayerWindedSpread").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerSpreadWalking, err = cfg.Section("Player").Key("PlayerSpreadWalking").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerSpreadStrafing, err = cfg.Section("Player").Key("PlayerSpreadStrafing").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerSpreadSprinting, err = cfg.Section("Player").Key("PlayerSpreadSprinting").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerSpreadRecovery, err = cfg.Section("Player").Key("PlayerSpreadRecovery").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerSpreadMax, err = cfg.Section("Player").Key("PlayerSpreadMax").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerAimToggle, err = cfg.Section("Player").Key("PlayerAimToggle").Bool()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerAimSpeedFactor, err = cfg.Section("Player").Key("PlayerAimSpeedFactor").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerAimRangeFactor, err = cfg.Section("Player").Key("PlayerAimRangeFactor").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerAimSpreadFactor, err = cfg.Section("Player").Key("PlayerAimSpreadFactor").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerStartingFlares, err = cfg.Section("Player").Key("PlayerStartingFlares").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerStartingBottles, err = cfg.Section("Player").Key("PlayerStartingBottles").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerThrowRange, err = cfg.Section("Player").Key("PlayerThrowRange").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	flareTime, err = cfg.Section("Player").Key("FlareTime").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	flareRadius, err = cfg.Section("Player").Key("FlareRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	flareLureRadius, err = cfg.Section("Player").Key("FlareLureRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	bottleNoiseRadius, err = cfg.Section("Player").Key("BottleNoiseRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	bottleLureTime, err = cfg.Section("Player").Key("BottleLureTime").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerShoveReach, err = cfg.Section("Player").Key("PlayerShoveReach").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerShoveRadius, err = cfg.Section("Player").Key("PlayerShoveRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerShoveSpeed, err = cfg.Section("Player").Key("PlayerShoveSpeed").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	playerShoveCooldown, err = cfg.Section("Player").Key("PlayerShoveCooldown").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieStunTime, err = cfg.Section("Zombie").Key("ZombieStunTime").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	pickupHealthAmount, err = cfg.Section("Player").Key("PickupHealthAmount").Int()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
@@ -62,6 +178,82 @@ func ApplyConfigs() {
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
+	zombieAttackRange, err = cfg.Section("Zombie").Key("ZombieAttackRange").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieAttackWindup, err = cfg.Section("Zombie").Key("ZombieAttackWindup").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieAttackSightTime, err = cfg.Section("Zombie").Key("ZombieAttackSightTime").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieDamage, err = cfg.Section("Zombie").Key("ZombieDamage").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieAmmoDropChance, err = cfg.Section("Zombie").Key("ZombieAmmoDropChance").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieTargetCommitment, err = cfg.Section("Zombie").Key("ZombieTargetCommitment").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieNoiseWeight, err = cfg.Section("Zombie").Key("ZombieNoiseWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieAggroWeight, err = cfg.Section("Zombie").Key("ZombieAggroWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieLoneDogWeight, err = cfg.Section("Zombie").Key("ZombieLoneDogWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieLoneDogRadius, err = cfg.Section("Zombie").Key("ZombieLoneDogRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieSeparationWeight, err = cfg.Section("Zombie").Key("ZombieSeparationWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieAlignmentWeight, err = cfg.Section("Zombie").Key("ZombieAlignmentWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	zombieCohesionWeight, err = cfg.Section("Zombie").Key("ZombieCohesionWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	directorMinSpawnInterval, err = cfg.Section("Director").Key("DirectorMinSpawnInterval").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	directorMaxSpawnInterval, err = cfg.Section("Director").Key("DirectorMaxSpawnInterval").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	directorMinSpawnLimit, err = cfg.Section("Director").Key("DirectorMinSpawnLimit").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	directorMaxSpawnLimit, err = cfg.Section("Director").Key("DirectorMaxSpawnLimit").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	directorCalmTime, err = cfg.Section("Director").Key("DirectorCalmTime").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	directorMemory, err = cfg.Section("Director").Key("DirectorMemory").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
 	dogWalkingSpeed, err = cfg.Section("Dog").Key("DogWalkingSpeed").Float64()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
@@ -94,8 +286,92 @@ func ApplyConfigs() {
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
+	dogComeRadius, err = cfg.Section("Dog").Key("DogComeRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogFleeSafetyWeight, err = cfg.Section("Dog").Key("DogFleeSafetyWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogFleePlayerWeight, err = cfg.Section("Dog").Key("DogFleePlayerWeight").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogMaxHealth, err = cfg.Section("Dog").Key("DogMaxHealth").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogInvulnerableTime, err = cfg.Section("Dog").Key("DogInvulnerableTime").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogHealthRegen, err = cfg.Section("Dog").Key("DogHealthRegen").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogInjuredSpeedFactor, err = cfg.Section("Dog").Key("DogInjuredSpeedFactor").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
 	outOfSightLimit, err = cfg.Section("Dog").Key("OutOfSightLimit").Int()
 	if err != nil {
 		log.Println("Error parsing INI file:", err)
 	}
+	dogHideRadius, err = cfg.Section("Dog").Key("DogHideRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogHiddenSightRadius, err = cfg.Section("Dog").Key("DogHiddenSightRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogHiddenAlpha, err = cfg.Section("Dog").Key("DogHiddenAlpha").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogCacheChance, err = cfg.Section("Dog").Key("DogCacheChance").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogCheckpointCacheChance, err = cfg.Section("Dog").Key("DogCheckpointCacheChance").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogThrowableCacheChance, err = cfg.Section("Dog").Key("DogThrowableCacheChance").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogStartingTrust, err = cfg.Section("Dog").Key("DogStartingTrust").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogTrustCloseGain, err = cfg.Section("Dog").Key("DogTrustCloseGain").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogTrustAloneLoss, err = cfg.Section("Dog").Key("DogTrustAloneLoss").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogTrustProtectGain, err = cfg.Section("Dog").Key("DogTrustProtectGain").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogTrustPetGain, err = cfg.Section("Dog").Key("DogTrustPetGain").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogTrustHurtLoss, err = cfg.Section("Dog").Key("DogTrustHurtLoss").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogAloneRadius, err = cfg.Section("Dog").Key("DogAloneRadius").Float64()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
+	dogMaxCalmTime, err = cfg.Section("Dog").Key("DogMaxCalmTime").Int()
+	if err != nil {
+		log.Println("Error parsing INI file:", err)
+	}
 }
@@ -3,14 +3,39 @@
 
 package main
 
-import "github.com/hajimehoshi/ebiten/v2"
+import (
+	"image/color"
+	"math"
 
-// Cursor represents the mouse cursor on the screen
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Size of the crosshair, in screen pixels
+const (
+	cursorArmLength = 3  // Length of each arm of the crosshair
+	cursorMinGap    = 2  // Distance of the arms from the middle at the least
+	cursorMaxGap    = 40 // Distance of the arms from the middle at the most
+)
+
+// cursorResultTime is how long (ticks) the crosshair shows whether a shot hit
+const cursorResultTime = 20
+
+// Colours of the crosshair
+var (
+	cursorColour     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	cursorHitColour  = color.RGBA{0xff, 0x00, 0x00, 0xff}
+	cursorMissColour = color.RGBA{0x80, 0x80, 0x80, 0xff}
+)
+
+// Cursor represents the mouse cursor on the screen, it is a crosshair which
+// opens up as far as the player's shots can spread where it points
 type Cursor struct {
-	Hit   bool // whether the shot hit or not
-	state int
+	Hit    bool // whether the shot hit or not
+	Result int  // ticks left showing whether the shot hit
+	state  int
 	// tick     int
-	images   []*ebiten.Image
+	gap      float64
 	position Coord
 }
 
@@ -18,42 +43,70 @@ const (
 	cursorNormal int = iota
 	cursorMiss
 	cursorHit
+	cursorAim
 )
 
 func NewCursor() *Cursor {
-	return &Cursor{
-		images: []*ebiten.Image{
-			loadImage("assets/sprites/Cursor_1.png"),
-			loadImage("assets/sprites/Cursor_2.png"),
-			loadImage("assets/sprites/Cursor_3.png"),
-		},
-	}
+	return &Cursor{}
+}
+
+// ShowResult shows on the crosshair whether a shot hit, once it is known,
+// which is only when the bullet stops for guns with bullets that fly
+func (c *Cursor) ShowResult(hit bool) {
+	c.Hit = hit
+	c.Result = cursorResultTime
 }
 
 func (c *Cursor) Update(g *GameScreen) {
 	cx, cy := ebiten.CursorPosition()
 	c.position.X, c.position.Y = float64(cx), float64(cy)
-	switch g.Player.State {
-	case playerDryFire:
+	switch {
+	case g.Player.State == playerDryFire:
 		c.state = cursorMiss
-	case playerShooting:
+	case c.Result > 0:
+		c.Result--
 		if c.Hit {
 			c.state = cursorHit
 		} else {
 			c.state = cursorMiss
 		}
+	case g.Player.Aiming:
+		c.state = cursorAim
 	default:
 		c.state = cursorNormal
 	}
+
+	// How far off the shots can land this far away from the player
+	px, py := g.Camera.GetScreenCoords(g.Player.Object.X, g.Player.Object.Y)
+	distance := CalcDistance(px, py, c.position.X, c.position.Y)
+	c.gap = distance * math.Tan(g.Player.Spread()/2)
+	c.gap = math.Max(cursorMinGap, math.Min(cursorMaxGap, c.gap))
 	return
 }
 
 func (c *Cursor) Draw(screen *ebiten.Image) {
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(c.position.X, c.position.Y)
-	op.GeoM.Translate( // position image centre around coords
-		float64(-c.images[c.state].Bounds().Dx())/2,
-		float64(-c.images[c.state].Bounds().Dy())/2,
-	)
-	screen.DrawImage(c.images[c.state], op)
+	x, y := math.Round(c.position.X), math.Round(c.position.Y)
+	gap := math.Round(c.gap)
+
+	// Four arms around the middle, greyed out when the shot missed or the gun is empty
+	clr := cursorColour
+	if c.state == cursorMiss {
+		clr = cursorMissColour
+	}
+	ebitenutil.DrawRect(screen, x-gap-cursorArmLength, y, cursorArmLength, 1, clr)
+	ebitenutil.DrawRect(screen, x+gap+1, y, cursorArmLength, 1, clr)
+	ebitenutil.DrawRect(screen, x, y-gap-cursorArmLength, 1, cursorArmLength, clr)
+	ebitenutil.DrawRect(screen, x, y+gap+1, 1, cursorArmLength, clr)
+
+	switch c.state {
+	case cursorHit:
+		// A red cross in the middle shows the shot hit
+		for i := -2.0; i <= 2; i++ {
+			ebitenutil.DrawRect(screen, x+i, y+i, 1, 1, cursorHitColour)
+			ebitenutil.DrawRect(screen, x+i, y-i, 1, 1, cursorHitColour)
+		}
+	case cursorAim:
+		// A dot in the middle shows the player is aiming down the sights
+		ebitenutil.DrawRect(screen, x, y, 1, 1, cursorColour)
+	}
 }
@@ -19,7 +19,7 @@ func init() {
 
 // DebugAim draws a line showing the direction and range of the gun
 func DebugAim(g *GameScreen, screen *ebiten.Image) {
-	rangeOfFire := g.Player.Range
+	rangeOfFire := g.Player.Range()
 	sX, sY := g.Camera.GetScreenCoords(
 		g.Player.Object.X-math.Cos(g.Player.Angle-math.Pi)*rangeOfFire,
 		g.Player.Object.Y-math.Sin(g.Player.Angle-math.Pi)*rangeOfFire,
@@ -29,4 +29,14 @@ func DebugAim(g *GameScreen, screen *ebiten.Image) {
 		g.Player.Object.Y,
 	)
 	ebitenutil.DrawLine(screen, pX, pY, sX, sY, color.Black)
+
+	// Where the last shots stopped
+	for _, s := range g.Shots {
+		x, y := g.Camera.GetScreenCoords(s.Impact.X, s.Impact.Y)
+		clr := color.RGBA{0xff, 0xff, 0xff, 0xff}
+		if s.Hit {
+			clr = color.RGBA{0xff, 0x00, 0x00, 0xff}
+		}
+		ebitenutil.DrawRect(screen, x-1, y-1, 3, 3, clr)
+	}
 }
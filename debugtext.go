@@ -28,12 +28,18 @@ func DebugText(g *GameScreen, screen *ebiten.Image) {
 			"X: %.2f\n"+
 			"Y: %.2f\n"+
 			"Zombies: %d\n"+
-			"Progress: %.2f%%\n",
+			"Pressure: %.2f\n"+
+			"Route: %d/%d %.2f%%\n"+
+			"Trust: %.2f\n",
 		ebiten.ActualFPS(),
 		ebiten.ActualTPS(),
 		g.Player.Object.X/32,
 		g.Player.Object.Y/32,
 		len(g.Zombies),
+		g.Director.Pressure,
+		g.Dog.Route.Current+1,
+		len(g.Dog.Route.Segments),
 		float64(g.Dog.MainPath.NextPoint)/float64(len(g.Dog.MainPath.Points))*100,
+		g.Dog.Trust,
 	))
 }
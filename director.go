@@ -0,0 +1,133 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Bounds of how often continuous spawn points spawn, the shortest interval
+// (ticks) is used under full pressure and the longest when the player is
+// given a breather
+var (
+	directorMinSpawnInterval int = 60
+	directorMaxSpawnInterval int = 300
+)
+
+// Bounds of how many zombies a continuous spawn point keeps alive, as a
+// multiplier of its initial count, under the least and the most pressure
+var (
+	directorMinSpawnLimit float64 = 0.5
+	directorMaxSpawnLimit float64 = 1.5
+)
+
+// directorCalmTime is how long (ticks) without a fight it takes for the
+// pressure to build up fully
+var directorCalmTime int = 1800
+
+// directorMemory is how long (ticks) it takes to forget a death or the dog
+// being in danger
+var directorMemory int = 3600
+
+// directorDogDangerTime is how long (ticks) the dog has to be in danger to
+// count as fully distressed
+const directorDogDangerTime = 600
+
+// directorSmoothing is how quickly the pressure follows the performance
+const directorSmoothing = 0.005
+
+// How much each sign of struggling lowers the pressure
+const (
+	directorDeathWeight = 0.4 // For every recent death
+	directorAmmoWeight  = 0.2 // For an empty clip
+	directorDogWeight   = 0.3 // For the dog being fully distressed
+)
+
+// Director watches how the run is going and sets the pressure of the zombie
+// spawns accordingly, players who are struggling get some breathing room and
+// players who are doing well get pushed harder
+type Director struct {
+	Pressure    float64 // From 0 for the least pressure to 1 for the most
+	Deaths      float64 // Recent deaths of the player and dog, slowly forgotten
+	DogDistress float64 // Recent time the dog was in danger, from 0 to 1
+	Calm        int     // Ticks since the last fight
+	SeenDeaths  int     // Deaths already counted
+	SeenShots   int     // Shots already counted
+}
+
+// NewDirector creates a director with neutral pressure
+func NewDirector(stat *Stat) *Director {
+	return &Director{
+		Pressure:   0.5,
+		SeenDeaths: stat.CounterPlayerDied + stat.CounterDogDied,
+		SeenShots:  stat.CounterBulletsFired,
+	}
+}
+
+// Update takes in how the run is going and moves the pressure towards what
+// suits it
+func (d *Director) Update(g *GameScreen) {
+	forget := 1 / float64(directorMemory)
+
+	deaths := g.Stat.CounterPlayerDied + g.Stat.CounterDogDied
+	d.Deaths = math.Max(0, d.Deaths+float64(deaths-d.SeenDeaths)-forget)
+	d.SeenDeaths = deaths
+
+	if g.Dog.Mode == dogDanger {
+		d.DogDistress = math.Min(1, d.DogDistress+1/float64(directorDogDangerTime))
+	} else {
+		d.DogDistress = math.Max(0, d.DogDistress-forget)
+	}
+
+	// Shooting, getting hurt or the dog running from zombies all mean a fight
+	if g.Stat.CounterBulletsFired != d.SeenShots || g.Player.Immunity > 0 || g.Dog.Mode == dogDanger {
+		d.Calm = 0
+	} else {
+		d.Calm++
+	}
+	d.SeenShots = g.Stat.CounterBulletsFired
+
+	target := directorPressure(
+		d.Deaths,
+		d.DogDistress,
+		g.Player.Gun().Supply(),
+		math.Min(1, float64(d.Calm)/float64(directorCalmTime)),
+	)
+	d.Pressure += (target - d.Pressure) * directorSmoothing
+}
+
+// directorPressure calculates the pressure suited to the performance, given
+// the recent deaths, the dog's distress, the fraction of ammo left and the
+// fraction of the calm time since the last fight
+func directorPressure(deaths, dogDistress, ammo, calm float64) float64 {
+	relief := deaths*directorDeathWeight + dogDistress*directorDogWeight + (1-ammo)*directorAmmoWeight
+	return math.Max(0, math.Min(1, 0.5+calm/2-relief))
+}
+
+// SpawnInterval returns a random number of ticks until the next continuous
+// spawn, which is shorter under more pressure
+func (d *Director) SpawnInterval() int {
+	interval := lerp(float64(directorMaxSpawnInterval), float64(directorMinSpawnInterval), d.Pressure)
+	base := int(math.Max(1, interval))
+	return base + rand.Intn(base)
+}
+
+// SpawnLimit returns how many zombies a continuous spawn point with the given
+// initial count keeps alive, which is more under more pressure
+func (d *Director) SpawnLimit(initialCount int) int {
+	scale := lerp(directorMinSpawnLimit, directorMaxSpawnLimit, d.Pressure)
+	return int(math.Round(float64(initialCount) * scale))
+}
+
+// Weight returns the weight of a spawn chance under the current pressure,
+// scaled by the pressure range of its archetype
+func (d *Director) Weight(c SpawnChance) float64 {
+	return c.Weight * lerp(c.Archetype.Pressure[0], c.Archetype.Pressure[1], d.Pressure)
+}
+
+// lerp interpolates linearly between a and b
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
@@ -0,0 +1,26 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestDirectorPressure(t *testing.T) {
+	tests := []struct {
+		name                            string
+		deaths, dogDistress, ammo, calm float64
+		want                            float64
+	}{
+		{"neutral", 0, 0, 1, 0, 0.5},
+		{"long calm", 0, 0, 1, 1, 1},
+		{"just died", 1, 0, 1, 0, 0.1},
+		{"struggling", 2, 1, 0, 0, 0},
+		{"dog in danger after calm", 0, 1, 1, 1, 0.7},
+	}
+	for _, tt := range tests {
+		got := directorPressure(tt.deaths, tt.dogDistress, tt.ammo, tt.calm)
+		if got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("%s: got pressure %f, want %f", tt.name, got, tt.want)
+		}
+	}
+}
@@ -47,9 +47,41 @@ var zombieSafeRadius float64 = 192
 // fleeingPathLength: the length of the path planned for fleeing
 var fleeingPathLength float64 = 200
 
+// How much the dog cares about getting far from the zombies and about
+// getting close to the player when it picks where to flee to
+var (
+	dogFleeSafetyWeight float64 = 1
+	dogFleePlayerWeight float64 = 0.5
+)
+
+// dogFleeAwayWeight is how much the dog prefers fleeing straight away from the zombies
+const dogFleeAwayWeight = 0.5
+
+// dogFleeReplanTime is how often (ticks) the dog picks a new place to flee to
+const dogFleeReplanTime = 30
+
 // how much time (ticks) the dog can be out of sight before it dies
 var outOfSightLimit int = 300
 
+// dogMaxHealth is how much health the dog starts with
+var dogMaxHealth float64 = 100
+
+// dogInvulnerableTime is how long (ticks) the dog can't be hurt after a hit
+var dogInvulnerableTime int = 60
+
+// dogHealthRegen is how much health the dog gets back per tick while sniffing at a checkpoint
+var dogHealthRegen float64 = 0.25
+
+// dogInjuredSpeedFactor is how fast the dog moves compared to normal when it
+// is nearly dead, it slows down gradually as it gets hurt
+var dogInjuredSpeedFactor float64 = 0.5
+
+// dogInjuredHealth is the fraction of health below which the dog limps
+const dogInjuredHealth = 0.6
+
+// dogLimpPeriod is how long (ticks) one step of the dog's limp takes
+const dogLimpPeriod = 24
+
 // Operating modes of the dog
 const (
 	dogNormal = iota // Dog is alive and no zombies in vicinity
@@ -64,13 +96,17 @@ const (
 	dogNormalBlocked
 	dogNormalSniffing
 	dogNormalWaitingAtCheckpoint
+	dogNormalStaying // Holding its position because the player said so
+	dogNormalComing  // Finding its way to the player because the player said so
+	dogNormalDigging // Sniffing around a spot along the way for something buried
 
 	dogDangerBarking
 	dogDangerFleeing
+	dogDangerHiding // Crouching under the trees until the zombies are gone
 )
 
 // Maps dog states to animation frames
-var dogStateToFrame = [7]int{2, 0, 2, 1, 2, 1, 0}
+var dogStateToFrame = [11]int{2, 0, 2, 1, 2, 2, 0, 1, 1, 0, 2}
 
 // Dog is player's companion
 type Dog struct {
@@ -83,6 +119,7 @@ type Dog struct {
 	PrevState            int
 	CurrentPath          *Path
 	MainPath             *Path
+	Route                *Route
 	OnMainPath           bool
 	LastPathCoord        Coord
 	Sprite               *SpriteSheet
@@ -90,23 +127,47 @@ type Dog struct {
 	LastPathpointReached bool
 	AtCheckpointCounter  int
 	OutOfSightCounter    int
+	Acknowledge          int
+	Health               float64
+	Immunity             int
+	NextSniff            int
+	SearchedCheckpoint   int
+	Trust                float64
+	PettedCheckpoint     int
+	Calming              int
+	Staying              bool // Whether the player told the dog to stay, it goes back to staying after danger
 }
 
 func (d *Dog) Init() {
 	d.TempSpeed = 1
-	d.CurrentPath = d.MainPath
-	d.OnMainPath = true
+	d.Health = dogMaxHealth
+	d.Trust = dogStartingTrust
+	d.NextSniff = int(dogSniffInterval.Random())
+	d.enterSegment(nil, 0)
 	d.turnTowardsPathPoint()
 }
 
 // Resets the dog to a coordinate after death
 func (d *Dog) Reset(cp int, x, y float64) {
 	d.OutOfSightCounter = 0
+	d.Acknowledge = 0
+	d.Health = dogMaxHealth
+	d.Immunity = 0
+	d.Calming = 0
+	d.Staying = false
 	d.Mode = dogNormal
 	d.State = dogNormalWaiting
+	// Carry on along the branch chosen before, but only look for the
+	// closest point on the segment after the checkpoint
+	d.Route.Current = d.Route.Segment(cp)
+	d.MainPath = d.Route.Path(d.Route.Current)
 	d.CurrentPath = d.MainPath
 	d.CurrentPath.NextPoint = d.findClosestPathPoint(x, y)
 	d.OnMainPath = true
+	d.LastPathpointReached = false
+	d.PrevCheckpoint = cp
+	d.SearchedCheckpoint = cp
+	d.NextSniff = int(dogSniffInterval.Random())
 	d.Object.X, d.Object.Y = x, y
 	d.turnTowardsPathPoint()
 }
@@ -147,14 +208,51 @@ func (d *Dog) zombiesInRange(zRange float64, g *GameScreen) (bool, float64, Coor
 	return closestZombie < zRange, closestZombie, resultantVectorCoord
 }
 
-// planFleeingRoute plans a fleeing route for the dog
+// planFleeingRoute plans a fleeing route for the dog around the walls to the
+// safest tile it can reach, away from the zombies and towards the player
 func (d *Dog) planFleeingRoute(vector Coord, g *GameScreen) *Path {
-	fleeingPath := &Path{}
-	nv := NormalizeVector(vector)
-	fleeingPath.Points = []Coord{
-		{X: d.Object.X + nv.X*fleeingPathLength, Y: d.Object.Y + nv.Y*fleeingPathLength},
+	start := tileAt(*d.Position())
+	from := g.LevelMap.Reachable(start, int(fleeingPathLength/gridSize))
+
+	// Only zombies that could be near one of the tiles matter
+	var zombies []*Coord
+	for _, z := range g.Zombies {
+		zp := z.Position()
+		if CalcDistance(zp.X, zp.Y, d.Object.X, d.Object.Y) < fleeingPathLength+zombieSafeRadius {
+			zombies = append(zombies, zp)
+		}
+	}
+
+	away := safeNormalize(vector)
+	best, bestScore := start, math.Inf(-1)
+	for tile := range from {
+		if score := d.fleeScore(g, tileCentre(tile), away, zombies); score > bestScore {
+			best, bestScore = tile, score
+		}
+	}
+
+	points := PathFrom(from, best)
+	if len(points) > 1 {
+		points = points[1:] // the dog is already on the first tile
+	}
+	return &Path{Points: points}
+}
+
+// fleeScore scores how good a place to flee to is
+func (d *Dog) fleeScore(g *GameScreen, c Coord, away Coord, zombies []*Coord) float64 {
+	direction := safeNormalize(Coord{X: c.X - d.Object.X, Y: c.Y - d.Object.Y})
+	straightAway := direction.X*away.X + direction.Y*away.Y
+
+	nearestZombie := zombieSafeRadius
+	for _, z := range zombies {
+		nearestZombie = math.Min(nearestZombie, CalcDistance(c.X, c.Y, z.X, z.Y))
 	}
-	return fleeingPath
+	safety := nearestZombie / zombieSafeRadius
+
+	playerDistance := CalcDistance(c.X, c.Y, g.Player.Object.X, g.Player.Object.Y)
+	nearPlayer := 1 - math.Min(1, playerDistance/(fleeingPathLength*2))
+
+	return straightAway*dogFleeAwayWeight + safety*dogFleeSafetyWeight + nearPlayer*d.fleePlayerWeight()
 }
 
 // planRouteBackToMainPath plans a route back to the main path
@@ -164,6 +262,10 @@ func (d *Dog) planRouteBackToMainPath(g *GameScreen) *Path {
 		Coord{X: d.Object.X, Y: d.Object.Y},
 		d.LastPathCoord,
 	)
+	if len(returnPath.Points) == 0 {
+		// No way around the walls, go straight back
+		returnPath.Points = []Coord{d.LastPathCoord}
+	}
 	returnPath.Points[len(returnPath.Points)-1] = d.LastPathCoord
 	returnPath.Points = GetBezierPathFromCoords(returnPath.Points, 2)
 	return returnPath
@@ -180,12 +282,19 @@ func (d *Dog) updateState(g *GameScreen) {
 		if zInRange {
 			d.Mode = dogDanger
 			d.State = dogDangerBarking
+			d.Calming = 0
 		}
 	case dogDanger:
+		// Calm down a while after the zombies are gone
 		zInRange, _, _ := d.zombiesInRange(zombieSafeRadius, g)
-		if !zInRange {
+		if zInRange {
+			d.Calming = 0
+		} else if d.Calming++; d.Calming >= d.calmTime() {
 			d.Mode = dogNormal
 			d.State = dogNormalWaiting
+			if d.Staying {
+				d.State = dogNormalStaying // keep holding position like it was told
+			}
 		}
 	}
 
@@ -196,16 +305,16 @@ func (d *Dog) updateState(g *GameScreen) {
 
 		switch d.State {
 		case dogNormalWaiting:
-			if d.PrevState == dogDangerFleeing {
+			if d.PrevState == dogDangerFleeing || d.PrevState == dogDangerHiding {
 				d.CurrentPath = d.planRouteBackToMainPath(g)
 				d.turnTowardsPathPoint()
 				d.OnMainPath = false
 			}
-			if playerDistance <= waitingRadius {
+			if playerDistance <= d.waitingRadius() {
 				d.State = dogNormalWalking
 			}
 		case dogNormalWalking:
-			if playerDistance > waitingRadius {
+			if playerDistance > d.waitingRadius() {
 				d.State = dogNormalWaiting
 			}
 			// Next state is set elsewhere
@@ -229,20 +338,43 @@ func (d *Dog) updateState(g *GameScreen) {
 		case dogNormalWaitingAtCheckpoint:
 			// Next state is set elsewhere
 			// - In case when player is also at the checkpoint
+		case dogNormalStaying:
+			// Next state is set by the player's commands
+		case dogNormalComing:
+			if playerDistance <= dogComeRadius {
+				d.State = dogNormalStaying
+			}
+		case dogNormalDigging:
+			if d.AtCheckpointCounter >= dogDiggingTime {
+				d.State = dogNormalWalking
+			}
 		}
 	case dogDanger:
-		zInRange, _, _ := d.zombiesInRange(zombieFleeRadius, g)
+		zInRange, _, resultantVector := d.zombiesInRange(zombieFleeRadius, g)
 
 		switch d.State {
 		case dogDangerBarking:
 			if zInRange {
 				if d.OnMainPath {
-					d.LastPathCoord = d.CurrentPath.Points[d.CurrentPath.NextPoint]
+					d.LastPathCoord = d.MainPath.Points[d.nextMainPathPoint()]
+				}
+				// Hide under the trees if there are any nearby, otherwise run
+				if path, ok := d.findCover(g, resultantVector); ok {
+					d.State = dogDangerHiding
+					d.CurrentPath = path
+					d.OnMainPath = false
+					d.turnTowardsPathPoint()
+				} else {
+					d.State = dogDangerFleeing
 				}
-				d.State = dogDangerFleeing
 			}
 		case dogDangerFleeing:
 			// Dog is fleeing until it changes mode to Normal
+		case dogDangerHiding:
+			// Dog is hiding until it changes mode to Normal, or a zombie finds it
+			if d.spotted(g) {
+				d.State = dogDangerFleeing
+			}
 		}
 	}
 }
@@ -255,15 +387,26 @@ func (d *Dog) Update(g *GameScreen) {
 	}
 
 	d.updateState(g)
+	d.updateTrust(g)
 
-	// If the dog is out of the screen for too long then it dies
-	sx, sy := g.Camera.GetScreenCoords(d.Object.X, d.Object.Y)
-	if sx < 0 || sy < 0 || sx > float64(g.Width) || sy > float64(g.Height) {
+	if d.Acknowledge > 0 {
+		d.Acknowledge--
+	}
+	if d.Immunity > 0 {
+		d.Immunity--
+	}
+
+	// If the dog is out of the screen for too long then it dies, unless it is
+	// staying where the player told it to and they know where it is
+	switch {
+	case d.State == dogNormalStaying:
+		// Paused until the dog moves again
+	case !g.isOnScreen(d.Object.X, d.Object.Y):
 		d.OutOfSightCounter++
 		if d.OutOfSightCounter > outOfSightLimit {
 			g.Dog.Mode = dogDead
 		}
-	} else {
+	default:
 		d.OutOfSightCounter = 0
 	}
 
@@ -275,11 +418,24 @@ func (d *Dog) Update(g *GameScreen) {
 		// If dog is walking back to the main path and reaches it then change its path to main path
 		// Try to move along the path
 		d.walk(g)
+		// Every now and then stop to sniff around for something buried
+		if d.OnMainPath && !d.LastPathpointReached {
+			d.NextSniff--
+			if d.NextSniff <= 0 {
+				d.NextSniff = int(dogSniffInterval.Random())
+				d.startSniffing(g, dogNormalDigging)
+			}
+		}
 	case dogNormalBlocked:
 		// Try to move along the path
 		d.walk(g)
 	case dogNormalSniffing:
 		d.AtCheckpointCounter++
+		d.Health = math.Min(dogMaxHealth, d.Health+dogHealthRegen)
+		if d.AtCheckpointCounter == dogCacheFindTime && d.SearchedCheckpoint < d.PrevCheckpoint {
+			d.SearchedCheckpoint = d.PrevCheckpoint
+			d.findCache(g, dogCheckpointCacheChance)
+		}
 		// Wait for the player to arrive at the same checkpoint
 	case dogNormalWaitingAtCheckpoint:
 		d.AtCheckpointCounter++
@@ -293,15 +449,27 @@ func (d *Dog) Update(g *GameScreen) {
 		if d.PrevState != dogDangerBarking {
 			g.Sounds[soundDogBark].Play()
 		}
+	case dogNormalStaying:
+		// Hold position
+	case dogNormalComing:
+		d.comeToPlayer(g)
+	case dogNormalDigging:
+		d.AtCheckpointCounter++
+		if d.AtCheckpointCounter == dogCacheFindTime {
+			d.findCache(g, dogCacheChance)
+		}
 	case dogDangerFleeing:
 		zInRange, _, resultantVector := d.zombiesInRange(zombieFleeRadius, g)
-		if zInRange {
+		if zInRange && (d.PrevState != dogDangerFleeing || g.Tick%dogFleeReplanTime == 0) {
 			d.CurrentPath = d.planFleeingRoute(resultantVector, g)
 			d.turnTowardsPathPoint()
 			d.OnMainPath = false
 		}
 		// Try to run along the path
 		d.followPath(g)
+	case dogDangerHiding:
+		// Run to the hiding place and crouch there
+		d.followPath(g)
 	}
 
 	// If dog is walking then after some time a flavour voice line is played
@@ -320,7 +488,11 @@ func (d *Dog) Update(g *GameScreen) {
 	}
 
 	// Animate dog
-	animationFrame := d.Sprite.Meta.FrameTags[dogStateToFrame[d.State]]
+	tag := dogStateToFrame[d.State]
+	if d.State == dogDangerHiding && !d.hidden() {
+		tag = dogStateToFrame[dogDangerFleeing] // still on its way to the trees
+	}
+	animationFrame := d.Sprite.Meta.FrameTags[tag]
 	d.Frame = Animate(d.Frame, g.Tick, animationFrame)
 	d.Object.Update()
 }
@@ -352,13 +524,15 @@ func (d *Dog) turnTowardsPathPoint() {
 	d.turnTowardsCoordinate(d.CurrentPath.Points[d.CurrentPath.NextPoint])
 }
 
+// followPlayer follows the player around the walls after the end of the main path
 func (d *Dog) followPlayer(g *GameScreen) {
-	d.turnTowardsCoordinate(Coord{X: g.Player.Object.X, Y: g.Player.Object.Y})
-
-	d.move(
-		math.Cos(d.Angle)*dogWalkingSpeed*d.TempSpeed,
-		math.Sin(d.Angle)*dogWalkingSpeed*d.TempSpeed,
-	)
+	if CalcDistance(d.Object.X, d.Object.Y, g.Player.Object.X, g.Player.Object.Y) <= dogComeRadius {
+		return // close enough
+	}
+	if d.OnMainPath {
+		d.planRouteToPlayer(g)
+	}
+	d.comeToPlayer(g)
 }
 
 // followPath moves the dog along its current path
@@ -372,12 +546,23 @@ func (d *Dog) followPath(g *GameScreen) {
 	if nextPathCoordDistance < 2 {
 		d.CurrentPath.NextPoint++
 		if d.CurrentPath.NextPoint == len(d.CurrentPath.Points) {
-			if d.State == dogNormalWalking {
+			if d.State == dogNormalComing || d.LastPathpointReached {
+				// Wait for the path to the player to be planned again
+				return
+			} else if d.State == dogDangerHiding {
+				// Stay in the hiding place
+				return
+			} else if d.State == dogNormalWalking {
 				// If the dog is normally walking, not fleeing
 				if d.OnMainPath {
-					// If the dog reaches the end of the main path then it finishes
-					d.LastPathpointReached = true
-					return
+					if d.Route.Current+1 < len(d.Route.Segments) {
+						// Carry on with the next segment of the route
+						d.enterSegment(g, d.Route.Current+1)
+					} else {
+						// If the dog reaches the end of the route then it finishes
+						d.LastPathpointReached = true
+						return
+					}
 				} else {
 					// If the dog reaches the main path again then it will continue on that
 					d.CurrentPath = d.MainPath
@@ -398,20 +583,22 @@ func (d *Dog) followPath(g *GameScreen) {
 	}
 
 	var speed float64
-	if d.State == dogDangerFleeing {
+	if d.State == dogDangerFleeing || d.State == dogDangerHiding || d.State == dogNormalComing {
 		speed = dogRunningSpeed
 	} else {
 		speed = dogWalkingSpeed
 	}
+	speed *= d.injuredSpeed(g)
 
 	d.move(
+		g,
 		math.Cos(d.Angle)*speed*d.TempSpeed,
 		math.Sin(d.Angle)*speed*d.TempSpeed,
 	)
 }
 
 // Move the Dog by the given vector if it is possible to do so
-func (d *Dog) move(dx, dy float64) {
+func (d *Dog) move(g *GameScreen, dx, dy float64) {
 	// Collision detection and response between sand trap and dog
 	d.TempSpeed = 1
 	if collision := d.Object.Check(0, 0, tagSandTrap); collision != nil {
@@ -435,9 +622,11 @@ func (d *Dog) move(dx, dy float64) {
 			// If the dog or the player has not been at the checkpoint before
 			if o.Data.(int) > d.PrevCheckpoint {
 				d.PrevCheckpoint = o.Data.(int)
+				d.startSniffing(g, dogNormalSniffing)
+			} else {
+				d.AtCheckpointCounter = 0
+				d.State = dogNormalSniffing
 			}
-			d.AtCheckpointCounter = 0
-			d.State = dogNormalSniffing
 		}
 	case dogNormalBlocked:
 		// If the dog would not collide with the player anymore
@@ -458,21 +647,80 @@ func (d *Dog) move(dx, dy float64) {
 	d.Object.Y += dy
 }
 
+// Hurt damages the dog unless it was hit very recently, it dies when it
+// has no health left
+func (d *Dog) Hurt(g *GameScreen, damage int) {
+	if d.Immunity > 0 || d.Mode == dogDead {
+		return
+	}
+	d.Health -= float64(damage)
+	d.Immunity = dogInvulnerableTime
+	d.changeTrust(-dogTrustHurtLoss)
+	g.Sounds[soundHit].Play()
+	g.Sounds[soundDogBark].Play()
+	if d.Health <= 0 {
+		d.Mode = dogDead
+	}
+}
+
+// injured returns whether the dog is hurt badly enough to limp
+func (d *Dog) injured() bool {
+	return d.Health < dogMaxHealth*dogInjuredHealth
+}
+
+// injuredSpeed returns the speed multiplier for how hurt the dog is, an
+// injured dog also limps, slowing down on every other step
+func (d *Dog) injuredSpeed(g *GameScreen) float64 {
+	hurt := 1 - math.Max(0, d.Health)/dogMaxHealth
+	factor := 1 - (1-dogInjuredSpeedFactor)*hurt
+	if d.injured() && (g.Tick/(dogLimpPeriod/2))%2 == 0 {
+		factor *= 0.5
+	}
+	return factor
+}
+
 // Draw draws the Dog to the screen
 func (d *Dog) Draw(g *GameScreen) {
+	// Blink while the dog can't be hurt
+	if d.Immunity > 0 && (d.Immunity/4)%2 == 0 {
+		d.drawAcknowledge(g)
+		return
+	}
+
+	d.drawSprite(g, &ebiten.DrawImageOptions{})
+	d.drawAcknowledge(g)
+}
+
+// DrawHidden draws the dog faintly over the trees while it hides under
+// them, so the player can still tell where it is
+func (d *Dog) DrawHidden(g *GameScreen) {
+	if d.Mode == dogDead || !d.hidden() {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.ColorM.Scale(1, 1, 1, dogHiddenAlpha)
+	d.drawSprite(g, op)
+}
+
+// drawSprite draws the dog's current frame with the given options
+func (d *Dog) drawSprite(g *GameScreen, op *ebiten.DrawImageOptions) {
 	// the centre of the dog's shoulders is 5px down from the middle
 	const centerOffset float64 = 5
 
 	s := d.Sprite
 	frame := s.Sprite[d.Frame]
-	op := &ebiten.DrawImageOptions{}
 
 	// Centre and rotate
 	op.GeoM.Translate(
 		float64(-frame.Position.W/2),
 		float64(-frame.Position.H/2)+centerOffset/2,
 	)
-	op.GeoM.Rotate(d.Angle + math.Pi/2)
+	// Lurch from side to side when limping
+	angle := d.Angle
+	if d.injured() && dogStateToFrame[d.State] == 0 {
+		angle += 0.15 * math.Sin(2*math.Pi*float64(g.Tick)/dogLimpPeriod)
+	}
+	op.GeoM.Rotate(angle + math.Pi/2)
 
 	g.Camera.Surface.DrawImage(
 		s.Image.SubImage(image.Rect(
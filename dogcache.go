@@ -0,0 +1,52 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math/rand"
+)
+
+// dogSniffInterval is how long (ticks) the dog walks between stopping to
+// sniff around for something buried
+var dogSniffInterval = FloatRange{900, 1800}
+
+// Chances of the dog finding a cache when it sniffs around
+var (
+	dogCacheChance           float64 = 0.5 // At a spot along the way
+	dogCheckpointCacheChance float64 = 1   // At a checkpoint
+)
+
+// dogThrowableCacheChance is the chance of a cache the dog finds holding a
+// flare or a bottle instead of ammo, unless the player needs health
+var dogThrowableCacheChance float64 = 0.25
+
+// dogDiggingTime is how long (ticks) the dog sniffs around a spot along the way
+const dogDiggingTime = 120
+
+// dogCacheFindTime is how long (ticks) the dog sniffs before it finds a cache
+const dogCacheFindTime = 60
+
+// startSniffing makes the dog stop and sniff the ground
+func (d *Dog) startSniffing(g *GameScreen, state int) {
+	d.AtCheckpointCounter = 0
+	d.State = state
+	g.Sounds[soundDogSniff].Play()
+}
+
+// findCache digs up a cache for the player if the dog is lucky, the more
+// hurt the player is the more likely it is to be something to heal with
+func (d *Dog) findCache(g *GameScreen, chance float64) {
+	if rand.Float64() >= chance {
+		return
+	}
+	kind := pickupAmmo
+	switch {
+	case rand.Float64() > float64(g.Player.Health)/float64(playerMaxHealth):
+		kind = pickupHealth
+	case rand.Float64() < dogThrowableCacheChance:
+		kind = pickupThrowable
+	}
+	g.DropPickup(kind, *d.Position())
+	g.Sounds[soundDogBark].Play()
+}
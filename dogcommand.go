@@ -0,0 +1,125 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// dogComeRadius is how close to the player the dog stops when called
+var dogComeRadius float64 = 32
+
+// dogComeReplanDistance is how far the player can move away from the end of
+// the dog's path before the dog plans a new one
+const dogComeReplanDistance = gridSize * 2
+
+// dogAcknowledgeTime is how long (ticks) the dog shows it heard a command
+const dogAcknowledgeTime = 30
+
+// dogAcknowledgeColour is the colour of the ring shown when the dog hears a command
+var dogAcknowledgeColour = color.NRGBA{0xff, 0xff, 0xff, 0xc0}
+
+// Commands the player can give the dog
+const (
+	dogCommandStay = iota // Hold position
+	dogCommandCome        // Come to the player
+	dogCommandGo          // Carry on along the route
+)
+
+// handleDogCommands gives the dog the command of the key that was pressed
+func (g *GameScreen) handleDogCommands() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyZ):
+		g.Dog.Command(g, dogCommandStay)
+	case inpututil.IsKeyJustPressed(ebiten.KeyC):
+		g.Dog.Command(g, dogCommandCome)
+	case inpututil.IsKeyJustPressed(ebiten.KeyV):
+		g.Dog.Command(g, dogCommandGo)
+	}
+}
+
+// Command makes the dog follow a command from the player, the dog only
+// listens when it is not running from zombies
+func (d *Dog) Command(g *GameScreen, command int) {
+	if d.Mode != dogNormal {
+		return
+	}
+
+	// Remember where to get back on the route
+	if d.OnMainPath {
+		d.LastPathCoord = d.MainPath.Points[d.nextMainPathPoint()]
+	}
+
+	d.Staying = command == dogCommandStay
+	switch command {
+	case dogCommandStay:
+		d.State = dogNormalStaying
+		d.turnTowardsCoordinate(*g.Player.Position())
+	case dogCommandCome:
+		d.State = dogNormalComing
+		d.planRouteToPlayer(g)
+	case dogCommandGo:
+		// Take the branch the player points towards when the route splits
+		cx, cy := g.Camera.GetCursorCoords()
+		d.Route.Hint = &Coord{X: cx, Y: cy}
+		if d.State != dogNormalStaying && d.State != dogNormalComing {
+			return // already on its way
+		}
+		d.State = dogNormalWalking
+		if !d.OnMainPath && !d.LastPathpointReached {
+			d.CurrentPath = d.planRouteBackToMainPath(g)
+			d.turnTowardsPathPoint()
+		}
+	}
+
+	d.Acknowledge = dogAcknowledgeTime
+	g.Sounds[soundDogBark].Play()
+}
+
+// nextMainPathPoint returns the index of the next point on the main path
+func (d *Dog) nextMainPathPoint() int {
+	if d.MainPath.NextPoint >= len(d.MainPath.Points) {
+		return len(d.MainPath.Points) - 1
+	}
+	return d.MainPath.NextPoint
+}
+
+// planRouteToPlayer plans a route to the player around the walls
+func (d *Dog) planRouteToPlayer(g *GameScreen) {
+	points := g.LevelMap.FindPath(*d.Position(), *g.Player.Position())
+	if len(points) == 0 {
+		// No way around the walls, go straight for the player
+		points = []Coord{*g.Player.Position()}
+	}
+	points[len(points)-1] = *g.Player.Position()
+	d.CurrentPath = &Path{Points: GetBezierPathFromCoords(points, 2)}
+	d.OnMainPath = false
+	d.turnTowardsPathPoint()
+}
+
+// comeToPlayer moves the dog along its route to the player, planning it
+// again when the player has moved away from where it ends
+func (d *Dog) comeToPlayer(g *GameScreen) {
+	end := d.CurrentPath.Points[len(d.CurrentPath.Points)-1]
+	moved := CalcDistance(end.X, end.Y, g.Player.Object.X, g.Player.Object.Y)
+	if moved > dogComeReplanDistance || d.CurrentPath.NextPoint == len(d.CurrentPath.Points) {
+		d.planRouteToPlayer(g)
+	}
+	d.followPath(g)
+}
+
+// drawAcknowledge shows a ring spreading out from the dog when it hears a command
+func (d *Dog) drawAcknowledge(g *GameScreen) {
+	if d.Acknowledge <= 0 {
+		return
+	}
+	x, y := g.surfaceCoords(d.Object.X, d.Object.Y)
+	radius := 8 + 16*float64(dogAcknowledgeTime-d.Acknowledge)/dogAcknowledgeTime
+	clr := dogAcknowledgeColour
+	clr.A = uint8(float64(clr.A) * float64(d.Acknowledge) / dogAcknowledgeTime)
+	drawRing(g, x, y, radius, clr)
+}
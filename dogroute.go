@@ -0,0 +1,176 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// routeBranchZombieRadius is how close to a branch zombies have to be to
+// make the dog avoid it
+var routeBranchZombieRadius float64 = 160
+
+// RouteSegment is the part of the dog's route from one checkpoint to the
+// next, it can have alternative branches which the dog chooses between when
+// it starts the segment
+type RouteSegment struct {
+	Checkpoint int       // Checkpoint the segment starts at, 0 for the start of the level
+	Branches   [][]Coord // Alternative paths, the first one is the main route
+	Chosen     int       // Index of the branch the dog is taking
+}
+
+// Route is the dog's route through the level, split up at the checkpoints
+type Route struct {
+	Segments []*RouteSegment // Segments in the order the dog walks them
+	Current  int             // Index of the segment the dog is on
+	Hint     *Coord          // Where the player pointed when telling the dog to go, used to choose a branch
+}
+
+// NewRoute splits the route up at the path points closest to the
+// checkpoints, given by their number, and adds the alternative branches, given
+// by the number of the checkpoint they start at. The ends of each branch are
+// joined up to the start and end of its segment.
+func NewRoute(points []Coord, checkpoints map[int]Coord, branches map[int][][]Coord) *Route {
+	numbers := make([]int, 0, len(checkpoints))
+	for n := range checkpoints {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	// Split at the closest point after the previous split, the route never goes back
+	route := &Route{}
+	start, startCheckpoint := 0, 0
+	for _, n := range numbers {
+		c := checkpoints[n]
+		split, best := start, math.Inf(1)
+		for i := start; i < len(points); i++ {
+			if d := CalcDistance(points[i].X, points[i].Y, c.X, c.Y); d < best {
+				split, best = i, d
+			}
+		}
+		if split > start {
+			route.Segments = append(route.Segments, &RouteSegment{
+				Checkpoint: startCheckpoint,
+				Branches:   [][]Coord{points[start:split]},
+			})
+			start = split
+		}
+		startCheckpoint = n
+	}
+	route.Segments = append(route.Segments, &RouteSegment{
+		Checkpoint: startCheckpoint,
+		Branches:   [][]Coord{points[start:]},
+	})
+
+	for i, s := range route.Segments {
+		from := s.Branches[0][0]
+		to := s.Branches[0][len(s.Branches[0])-1]
+		if i+1 < len(route.Segments) {
+			to = route.Segments[i+1].Branches[0][0]
+		}
+		for _, b := range branches[s.Checkpoint] {
+			joined := append(append([]Coord{from}, b...), to)
+			s.Branches = append(s.Branches, GetBezierPathFromCoords(joined, 4))
+		}
+	}
+
+	return route
+}
+
+// Segment returns the index of the segment the dog is on after the checkpoint
+func (r *Route) Segment(checkpoint int) int {
+	segment := 0
+	for i, s := range r.Segments {
+		if s.Checkpoint <= checkpoint {
+			segment = i
+		}
+	}
+	return segment
+}
+
+// Choose picks the branch of a segment, the one the player pointed towards
+// or else the one with the fewest zombies near it
+func (r *Route) Choose(segment int, zombies []*Coord) {
+	s := r.Segments[segment]
+	if len(s.Branches) < 2 {
+		s.Chosen = 0
+		r.Hint = nil
+		return
+	}
+
+	if r.Hint != nil {
+		s.Chosen = closestBranch(s.Branches, *r.Hint)
+		r.Hint = nil
+		return
+	}
+
+	fewest := -1
+	for i, b := range s.Branches {
+		count := zombiesNearBranch(b, zombies)
+		if fewest < 0 || count < fewest {
+			s.Chosen, fewest = i, count
+		}
+	}
+}
+
+// Path returns the chosen branch of a segment as a new path for the dog to follow
+func (r *Route) Path(segment int) *Path {
+	s := r.Segments[segment]
+	return &Path{Points: s.Branches[s.Chosen]}
+}
+
+// zombiesNearBranch counts the zombies near the branch, away from its ends
+// which all the branches of a segment share
+func zombiesNearBranch(branch []Coord, zombies []*Coord) int {
+	first, last := branch[0], branch[len(branch)-1]
+	var points []Coord
+	for _, p := range branch {
+		if CalcDistance(p.X, p.Y, first.X, first.Y) > routeBranchZombieRadius &&
+			CalcDistance(p.X, p.Y, last.X, last.Y) > routeBranchZombieRadius {
+			points = append(points, p)
+		}
+	}
+
+	count := 0
+	for _, z := range zombies {
+		for _, p := range points {
+			if CalcDistance(p.X, p.Y, z.X, z.Y) < routeBranchZombieRadius {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// closestBranch returns the branch which passes closest to the point in its
+// first half, where the branches split up
+func closestBranch(branches [][]Coord, point Coord) int {
+	closest, best := 0, math.Inf(1)
+	for i, b := range branches {
+		for _, p := range b[:len(b)/2+1] {
+			if d := CalcDistance(p.X, p.Y, point.X, point.Y); d < best {
+				closest, best = i, d
+			}
+		}
+	}
+	return closest
+}
+
+// enterSegment puts the dog on a segment of its route, choosing which
+// branch to take
+func (d *Dog) enterSegment(g *GameScreen, segment int) {
+	var zombies []*Coord
+	if g != nil {
+		for _, z := range g.Zombies {
+			zombies = append(zombies, z.Position())
+		}
+	}
+	d.Route.Current = segment
+	d.Route.Choose(segment, zombies)
+	d.MainPath = d.Route.Path(segment)
+	d.CurrentPath = d.MainPath
+	d.OnMainPath = true
+}
@@ -0,0 +1,50 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestNewRoute(t *testing.T) {
+	// A straight route along the X axis with checkpoints at 400 and 800, and
+	// a branch going round a big bend between them
+	var points []Coord
+	for x := 0; x < 1200; x += 10 {
+		points = append(points, Coord{X: float64(x)})
+	}
+	checkpoints := map[int]Coord{1: {X: 400}, 2: {X: 800}}
+	branches := map[int][][]Coord{1: {{{X: 600, Y: 800}}}}
+
+	route := NewRoute(points, checkpoints, branches)
+	if len(route.Segments) != 3 {
+		t.Fatalf("Route has %d segments, want 3", len(route.Segments))
+	}
+	for i, want := range []struct{ checkpoint, first, branches int }{
+		{0, 0, 1},
+		{1, 400, 2},
+		{2, 800, 1},
+	} {
+		s := route.Segments[i]
+		if s.Checkpoint != want.checkpoint || s.Branches[0][0].X != float64(want.first) || len(s.Branches) != want.branches {
+			t.Errorf("Segment %d starts at checkpoint %d, X %f with %d branches, want %d, %d with %d",
+				i, s.Checkpoint, s.Branches[0][0].X, len(s.Branches), want.checkpoint, want.first, want.branches)
+		}
+	}
+
+	if got := route.Segment(2); got != 2 {
+		t.Errorf("Segment after checkpoint 2 is %d, want 2", got)
+	}
+
+	// Zombies on the main route make the dog take the branch
+	route.Choose(1, []*Coord{{X: 600}})
+	if got := route.Segments[1].Chosen; got != 1 {
+		t.Errorf("Chose branch %d with zombies on the main route, want 1", got)
+	}
+
+	// Unless the player points at the main route
+	route.Hint = &Coord{X: 500}
+	route.Choose(1, []*Coord{{X: 600}})
+	if got := route.Segments[1].Chosen; got != 0 {
+		t.Errorf("Chose branch %d when the player pointed at the main route, want 0", got)
+	}
+}
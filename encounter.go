@@ -0,0 +1,95 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/solarlune/resolv"
+)
+
+// arenaWallThickness is how thick the walls locking the arena are
+const arenaWallThickness = 16
+
+// arenaLockMargin is how far inside the arena the player has to be for it to lock
+const arenaLockMargin = 32
+
+// arenaColour is the colour of the arena boundary while it is locked
+var arenaColour = color.RGBA{0xc8, 0x44, 0x13, 0x80}
+
+// Encounter is a boss fight, it locks the player in an arena around the boss,
+// and shows the boss's health until the boss dies
+type Encounter struct {
+	Boss          *Boss            // The boss being fought
+	X, Y          float64          // Top-left corner of the arena
+	Width, Height float64          // Size of the arena
+	Walls         []*resolv.Object // Walls around the arena while it is locked
+	Locked        bool             // Whether the player is locked in the arena
+}
+
+// NewEncounter sets up a boss fight in an arena around the centre
+func NewEncounter(boss *Boss, centre Coord) *Encounter {
+	arena := boss.Definition.Arena
+	return &Encounter{
+		Boss:   boss,
+		X:      centre.X - arena.Width/2,
+		Y:      centre.Y - arena.Height/2,
+		Width:  arena.Width,
+		Height: arena.Height,
+	}
+}
+
+// Update locks the arena when the player walks into it and ends the fight when
+// the boss is defeated
+func (e *Encounter) Update(g *GameScreen) {
+	if e.Boss.Dead {
+		e.End(g)
+		g.Encounter = nil
+		return
+	}
+
+	if !e.Locked && e.Width > 0 && e.Height > 0 {
+		px, py := g.Player.Object.X, g.Player.Object.Y
+		if px > e.X+arenaLockMargin && px < e.X+e.Width-arenaLockMargin &&
+			py > e.Y+arenaLockMargin && py < e.Y+e.Height-arenaLockMargin {
+			e.Lock(g)
+		}
+	}
+}
+
+// Lock closes the walls around the arena
+func (e *Encounter) Lock(g *GameScreen) {
+	t := float64(arenaWallThickness)
+	for _, r := range [][4]float64{
+		{e.X - t, e.Y - t, e.Width + 2*t, t},        // North
+		{e.X - t, e.Y + e.Height, e.Width + 2*t, t}, // South
+		{e.X - t, e.Y, t, e.Height},                 // West
+		{e.X + e.Width, e.Y, t, e.Height},           // East
+	} {
+		wall := resolv.NewObject(r[0], r[1], r[2], r[3], tagWall)
+		wall.SetShape(resolv.NewRectangle(r[0], r[1], r[2], r[3]))
+		g.Space.Add(wall)
+		e.Walls = append(e.Walls, wall)
+	}
+	e.Locked = true
+}
+
+// End opens the arena again
+func (e *Encounter) End(g *GameScreen) {
+	g.Space.Remove(e.Walls...)
+	e.Walls = nil
+	e.Locked = false
+}
+
+// Draw draws the boundary of the arena while it is locked
+func (e *Encounter) Draw(g *GameScreen) {
+	if !e.Locked {
+		return
+	}
+	for _, w := range e.Walls {
+		x, y := g.surfaceCoords(w.X, w.Y)
+		ebitenutil.DrawRect(g.Camera.Surface, x, y, w.W, w.H, arenaColour)
+	}
+}
@@ -0,0 +1,164 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+)
+
+// zombieNeighbourRadius is how far a zombie looks for other zombies to flock with
+var zombieNeighbourRadius float64 = 32
+
+// zombieSeparationRadius is how close another zombie can get before it is pushed away
+var zombieSeparationRadius float64 = 12
+
+// zombieSurroundRadius is the distance within which zombies stop flanking and go straight in
+var zombieSurroundRadius float64 = 96
+
+// zombieMaxNeighbours caps how many neighbours are considered to keep big hordes cheap
+const zombieMaxNeighbours = 8
+
+// zombieMaxFlank is the largest angle (radians) a zombie deviates from its target to flank it
+const zombieMaxFlank = 0.7
+
+// zombieSteeringSmoothing is how quickly the velocity follows the desired direction (0 to 1)
+const zombieSteeringSmoothing = 0.2
+
+// Weights of the steering behaviours, the seek weight is always 1
+var (
+	zombieSeparationWeight float64 = 1.5
+	zombieAlignmentWeight  float64 = 0.3
+	zombieCohesionWeight   float64 = 0.2
+)
+
+// Angles tried when the path in front of a zombie is blocked by a wall
+var zombieFeelerAngles = []float64{math.Pi / 4, -math.Pi / 4, math.Pi / 2, -math.Pi / 2}
+
+// neighbours returns the zombies in the cells around this zombie which are
+// within the neighbour radius, at most zombieMaxNeighbours of them
+func (z *Zombie) neighbours(g *GameScreen) []*Zombie {
+	var result []*Zombie
+
+	cx, cy := g.Space.WorldToSpace(z.Object.X-zombieNeighbourRadius, z.Object.Y-zombieNeighbourRadius)
+	ex, ey := g.Space.WorldToSpace(z.Object.X+zombieNeighbourRadius, z.Object.Y+zombieNeighbourRadius)
+	for y := cy; y <= ey; y++ {
+		for x := cx; x <= ex; x++ {
+			cell := g.Space.Cell(x, y)
+			if cell == nil {
+				continue
+			}
+			for _, o := range cell.Objects {
+				var n *Zombie
+				switch data := o.Data.(type) {
+				case *Zombie:
+					n = data
+				case *Boss:
+					n = data.Zombie
+				}
+				if n == nil || n == z || !o.HasTags(tagMob) {
+					continue
+				}
+				if CalcDistance(z.Object.X, z.Object.Y, n.Object.X, n.Object.Y) > zombieNeighbourRadius {
+					continue
+				}
+				// Objects span several cells so they can be found more than once
+				seen := false
+				for _, r := range result {
+					if r == n {
+						seen = true
+						break
+					}
+				}
+				if !seen {
+					result = append(result, n)
+					if len(result) == zombieMaxNeighbours {
+						return result
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// steer calculates the direction the zombie wants to move in, combining
+// seeking its target with separation, alignment and cohesion of the horde
+func (z *Zombie) steer(g *GameScreen) Coord {
+	toTarget := Coord{X: z.Target.X - z.Object.X, Y: z.Target.Y - z.Object.Y}
+	targetDistance := CalcDistance(toTarget.X, toTarget.Y, 0, 0)
+
+	// Far away zombies take a wide arc so the horde closes in from all sides
+	flank := z.Flank * math.Min(1, targetDistance/zombieSurroundRadius)
+	seek := rotateVector(safeNormalize(toTarget), flank)
+
+	var separation, alignment, centre Coord
+	neighbours := z.neighbours(g)
+	for _, n := range neighbours {
+		dx, dy := z.Object.X-n.Object.X, z.Object.Y-n.Object.Y
+		d := CalcDistance(dx, dy, 0, 0)
+		if d < zombieSeparationRadius {
+			if d == 0 {
+				// Exactly on top of each other, push in a random direction
+				dx, dy, d = math.Cos(z.Flank), math.Sin(z.Flank), 1
+			}
+			// Closer neighbours push harder
+			strength := (zombieSeparationRadius - d) / zombieSeparationRadius
+			separation.X += dx / d * strength
+			separation.Y += dy / d * strength
+		}
+		alignment.X += n.Velocity.X
+		alignment.Y += n.Velocity.Y
+		centre.X += n.Object.X
+		centre.Y += n.Object.Y
+	}
+
+	desired := seek
+	if len(neighbours) > 0 {
+		count := float64(len(neighbours))
+		cohesion := safeNormalize(Coord{X: centre.X/count - z.Object.X, Y: centre.Y/count - z.Object.Y})
+		alignment = safeNormalize(alignment)
+		desired.X += separation.X*zombieSeparationWeight + alignment.X*zombieAlignmentWeight + cohesion.X*zombieCohesionWeight
+		desired.Y += separation.Y*zombieSeparationWeight + alignment.Y*zombieAlignmentWeight + cohesion.Y*zombieCohesionWeight
+	}
+	desired = safeNormalize(desired)
+
+	return z.avoidWalls(g, desired)
+}
+
+// avoidWalls turns the direction away from walls directly in front of the
+// zombie, using the level map so it costs only a few lookups
+func (z *Zombie) avoidWalls(g *GameScreen, dir Coord) Coord {
+	feeler := float64(gridSize) / 2
+	ahead := Coord{X: z.Object.X + dir.X*feeler, Y: z.Object.Y + dir.Y*feeler}
+	if g.LevelMap.isFreeAtCoord(ahead) {
+		return dir
+	}
+	for _, a := range zombieFeelerAngles {
+		try := rotateVector(dir, a)
+		ahead = Coord{X: z.Object.X + try.X*feeler, Y: z.Object.Y + try.Y*feeler}
+		if g.LevelMap.isFreeAtCoord(ahead) {
+			return try
+		}
+	}
+	return dir
+}
+
+// safeNormalize normalizes the vector, returning a zero vector instead of NaN
+// if its length is zero
+func safeNormalize(vector Coord) Coord {
+	if vector.X == 0 && vector.Y == 0 {
+		return vector
+	}
+	return NormalizeVector(vector)
+}
+
+// rotateVector rotates the vector by the given angle in radians
+func rotateVector(vector Coord, angle float64) Coord {
+	sin, cos := math.Sincos(angle)
+	return Coord{
+		X: vector.X*cos - vector.Y*sin,
+		Y: vector.X*sin + vector.Y*cos,
+	}
+}
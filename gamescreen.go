@@ -15,7 +15,6 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
-	beziercp "github.com/brothertoad/bezier"
 	camera "github.com/melonfunction/ebiten-camera"
 	"github.com/solarlune/ldtkgo"
 	"github.com/solarlune/resolv"
@@ -71,14 +70,23 @@ type GameScreen struct {
 	Camera         *camera.Camera
 	Cursor         *Cursor
 	Sprites        map[SpriteType]*SpriteSheet
-	ZombieSprites  []*SpriteSheet
+	ZombieDefs     *ZombieDefinitions
 	Player         *Player
 	Dog            *Dog
 	SpawnPoints    SpawnPoints
+	Director       *Director
 	Zombies        Zombies
+	Noises         Noises
+	Pickups        Pickups
+	Shots          []Shot
+	Projectiles    Projectiles
+	Throwables     Throwables
+	MapPickups     Pickups
 	BossDefeated   bool
+	Encounter      *Encounter
 	Space          *resolv.Space
 	LevelMap       LevelMap
+	Cover          Cover
 	Checkpoint     int
 	HUD            *HUD
 	Debuggers      Debuggers
@@ -186,6 +194,13 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 		}
 	}
 
+	// Find where the dog can hide under the trees, once the walls are known
+	for _, layer := range level.Layers {
+		if layer.Identifier == "Treetops" {
+			g.Cover = NewCover(layer, g.LevelMap)
+		}
+	}
+
 	// SoundLoops
 	*loadingCount++
 	g.Music = NewMusicPlayer(loadSoundFile("assets/music/BackgroundMusic.ogg", sampleRate))
@@ -193,7 +208,7 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 
 	// Sound
 	*loadingCount++
-	howManySounds := 13
+	howManySounds := 7
 	g.Sounds = make([]*Sound, howManySounds)
 	for i := 0; i < howManySounds; i++ {
 		g.Sounds[i] = &Sound{Volume: 0.7}
@@ -204,13 +219,7 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	g.Sounds[soundPlayerDies].AddSound("assets/sfx/PlayerDies", sampleRate, context)
 	g.Sounds[soundHit].AddSound("assets/sfx/Hit", sampleRate, context, 5)
 	g.Sounds[soundDryFire].AddSound("assets/sfx/Gun-dry-fire", sampleRate, context)
-	g.Sounds[soundZombieScream].AddSound("assets/sfx/Zombie-scream", sampleRate, context)
-	g.Sounds[soundZombieGrowl].AddSound("assets/sfx/Zombie-growl", sampleRate, context, 4)
-	g.Sounds[soundZombieDeath].AddSound("assets/sfx/Zombie-Death", sampleRate, context, 2)
-	g.Sounds[soundBigZombieSound].AddSound("assets/sfx/Big-zombie-sound", sampleRate, context, 4)
-	g.Sounds[soundBigZombieDeath1].AddSound("assets/sfx/Big-zombie-death-Phase-1", sampleRate, context)
-	g.Sounds[soundBigZombieScream].AddSound("assets/sfx/Big-zombie-scream-Phase-2", sampleRate, context)
-	g.Sounds[soundBigZombieDeath2].AddSound("assets/sfx/Big-zombie-death-Phase-2", sampleRate, context)
+	g.Sounds[soundDogSniff].AddSound("assets/sfx/Dog-sniffing", sampleRate, context)
 
 	// Voices
 	howManyVoices := 5
@@ -228,16 +237,12 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 
 	// Load sprites
 	*loadingCount++
-	g.Sprites = make(map[SpriteType]*SpriteSheet, 5)
+	g.Sprites = make(map[SpriteType]*SpriteSheet, 2)
 	g.Sprites[spritePlayer] = loadSprite("Player")
 	g.Sprites[spriteDog] = loadSprite("Dog")
-	g.Sprites[spriteZombieSprinter] = loadSprite("Zombie_sprinter")
-	g.Sprites[spriteZombieBig] = loadSprite("Zombie_big")
-	g.Sprites[spriteZombieCrawler] = loadSprite("Zombie_crawler")
-	g.ZombieSprites = make([]*SpriteSheet, zombieVariants)
-	for index := 0; index < zombieVariants; index++ {
-		g.ZombieSprites[index] = loadSprite("Zombie_" + strconv.Itoa(index))
-	}
+
+	// Load zombie archetypes with their sprites and sounds
+	g.ZombieDefs = loadZombieDefinitions("assets/zombies.json")
 
 	// Load entities from map
 	*loadingCount++
@@ -258,9 +263,18 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 
 	// Add player to the game
 	playerPosition := entities.EntityByIdentifier("Player").Position
-	g.Player = NewPlayer(playerPosition, g.Sprites[spritePlayer])
+	g.Player = NewPlayer(playerPosition, g.Sprites[spritePlayer], loadWeapons("assets/weapons.json"))
 	g.Space.Add(g.Player.Object)
 
+	// Add ammo lying around the map
+	for _, e := range entities.Entities {
+		if e.Identifier == "Ammo" {
+			g.MapPickups = append(g.MapPickups, NewMapPickup(e))
+		}
+	}
+	g.Pickups = g.MapPickups.Remaining()
+
+	checkpoints := map[int]Coord{}
 	for _, e := range entities.Entities {
 		if strings.HasPrefix(e.Identifier, "Checkpoint") {
 			eid, err := strconv.Atoi(e.Identifier[11:])
@@ -281,22 +295,27 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 			)
 			obj.Data = eid
 			g.Space.Add(obj)
+			checkpoints[eid] = Coord{X: float64(e.Position[0]) + float64(w)/2, Y: float64(e.Position[1]) + float64(h)/2}
 		}
 	}
 
 	// Load the dog's path
 	dogEntity := entities.EntityByIdentifier("Dog")
-	pathArray := dogEntity.PropertyByIdentifier("Path").AsArray()
 	// Start with the dog's current position
-	pathPoints := []beziercp.PointF{{X: float64(dogEntity.Position[0]), Y: float64(dogEntity.Position[1])}}
-	for _, pathCoord := range pathArray {
-		pathPoints = append(pathPoints, beziercp.PointF{
-			X: (pathCoord.(map[string]any)["cx"].(float64) + 0.5) * float64(entities.GridSize),
-			Y: (pathCoord.(map[string]any)["cy"].(float64) + 0.5) * float64(entities.GridSize),
-		})
-	}
+	pathPoints := append(
+		[]Coord{{X: float64(dogEntity.Position[0]), Y: float64(dogEntity.Position[1])}},
+		entityPath(dogEntity, entities.GridSize)...,
+	)
+	dogPath := GetBezierPathFromCoords(pathPoints, 4)
 
-	dogPath := GetBezierPath(pathPoints, 4)
+	// Load the alternative branches of the dog's route
+	branches := map[int][][]Coord{}
+	for _, e := range entities.Entities {
+		if e.Identifier == "Dog_branch" {
+			checkpoint := e.PropertyByIdentifier("Checkpoint").AsInt()
+			branches[checkpoint] = append(branches[checkpoint], entityPath(e, entities.GridSize))
+		}
+	}
 
 	// Add dog to the game
 	object := resolv.NewObject(
@@ -310,33 +329,28 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	))
 	object.Shape.(*resolv.ConvexPolygon).RecenterPoints()
 	g.Dog = &Dog{
-		Object:   object,
-		Sprite:   g.Sprites[spriteDog],
-		MainPath: &Path{Points: dogPath, NextPoint: 0},
+		Object: object,
+		Sprite: g.Sprites[spriteDog],
+		Route:  NewRoute(dogPath, checkpoints, branches),
 	}
 	g.Dog.Init()
 	g.Space.Add(g.Dog.Object)
 
 	// Add spawnpoints to the game
 	for _, e := range entities.Entities {
-		if e.Identifier == "Zombie" || e.Identifier == "Zombie_sprinter" || e.Identifier == "Zombie_big" {
-			ztype := zombieNormal
-			if e.Identifier == "Zombie_sprinter" {
-				ztype = zombieSprinter
-			} else if e.Identifier == "Zombie_big" {
-				ztype = zombieBig
-			}
+		if mix, ok := g.ZombieDefs.Spawners[e.Identifier]; ok {
 			initialCount := e.PropertyByIdentifier("Initial").AsInt()
 			continuous := e.PropertyByIdentifier("Continuous").AsBool()
 			g.SpawnPoints = append(g.SpawnPoints, &SpawnPoint{
 				Position:     Coord{X: float64(e.Position[0]), Y: float64(e.Position[1])},
 				InitialCount: initialCount,
 				Continuous:   continuous,
-				ZombieType:   ztype,
+				Mix:          mix,
 			})
 		}
 	}
 
+	g.Director = NewDirector(g.Stat)
 	g.HUD = NewHUD()
 	g.Zoom = NewZoom()
 
@@ -347,6 +361,19 @@ func NewGameScreen(game *Game, loadingCount LoadingCounter) {
 	game.StateLock.Unlock()
 }
 
+// entityPath returns the points of an entity's Path property as coordinates
+// of the centres of the grid cells
+func entityPath(e *ldtkgo.Entity, gridSize int) []Coord {
+	var points []Coord
+	for _, pathCoord := range e.PropertyByIdentifier("Path").AsArray() {
+		points = append(points, Coord{
+			X: (pathCoord.(map[string]any)["cx"].(float64) + 0.5) * float64(gridSize),
+			Y: (pathCoord.(map[string]any)["cy"].(float64) + 0.5) * float64(gridSize),
+		})
+	}
+	return points
+}
+
 func (g *GameScreen) Start() {
 	g.Music.Play()
 	g.Stat.GameStarted = time.Now()
@@ -367,6 +394,16 @@ func (g *GameScreen) Reset(game *Game) {
 		g.Zombies[i] = nil
 	}
 	g.Zombies = Zombies{}
+	g.Noises = Noises{}
+	g.Projectiles = Projectiles{}
+	g.Throwables = Throwables{}
+	g.Pickups = g.MapPickups.Remaining()
+
+	// Call off any boss fight
+	if g.Encounter != nil {
+		g.Encounter.End(g)
+		g.Encounter = nil
+	}
 
 	// Reset spawnpoints
 	for _, s := range g.SpawnPoints {
@@ -374,7 +411,21 @@ func (g *GameScreen) Reset(game *Game) {
 	}
 
 	// Reset some player and dog values
-	g.Player.Ammo = playerAmmoClipMax
+	for _, gun := range g.Player.Guns {
+		gun.Rearm()
+	}
+	g.Player.Restock()
+	g.Player.Cooldown = 0
+	g.Player.Reloading = 0
+	g.Player.ShoveCooldown = 0
+	g.Player.Stamina = 1
+	g.Player.Winded = false
+	g.Player.Aiming = false
+	g.Player.Bloom = 0
+	g.Player.Health = playerMaxHealth
+	g.Player.Immunity = 0
+	g.Player.Knockback = Coord{}
+	g.Player.Staggered = 0
 	startPos := entities.EntityByIdentifier("Player").Position
 	if g.Checkpoint > 0 {
 		startPos = entities.EntityByIdentifier(
@@ -397,7 +448,7 @@ func (g *GameScreen) Update() (GameState, error) {
 
 	// Pressing X any time quits immediately
 	if ebiten.IsKeyPressed(ebiten.KeyX) {
-		// literally copied this whole code from the player-zombie collision section below
+		// literally copied this whole code from the player death section below
 		log.Println("game reset manually by player!")
 		g.Music.Pause()
 		g.Sounds[soundPlayerDies].Play()
@@ -420,6 +471,20 @@ func (g *GameScreen) Update() (GameState, error) {
 		}
 	}
 
+	// Pressing Z, C or V tells the dog to stay, come or go
+	g.handleDogCommands()
+	g.handlePetting()
+	g.handleShove()
+
+	// Pressing G throws a flare or a bottle, T switches between them
+	g.handleThrowing()
+
+	// Pressing the number keys switches guns
+	g.handleGunSwitching()
+
+	// Holding the right mouse button aims down the sights
+	g.handleAiming()
+
 	// Gun shooting handler
 	if clicked() {
 		Shoot(g)
@@ -432,31 +497,43 @@ func (g *GameScreen) Update() (GameState, error) {
 	// Update player
 	g.Player.Update(g)
 
+	// Move bullets in flight
+	g.Projectiles.Update(g)
+
+	// Move things that were thrown
+	g.Throwables.Update(g)
+
 	// Update dog
 	g.Dog.Update(g)
 
-	// Update zombies
+	// Pick up what the dog found
+	g.Pickups.Update(g)
+
+	// Update zombies and the noises they hear
 	g.Zombies.Update(g)
+	g.Noises.Update()
 
-	// Update spawn points
+	// Update spawn pressure and spawn points
+	g.Director.Update(g)
 	g.SpawnPoints.Update(g)
 
+	// Update boss fight
+	if g.Encounter != nil {
+		g.Encounter.Update(g)
+	}
+
 	// Update cursor
 	g.Cursor.Update(g)
 
 	// Update music
 	g.Music.Update()
 
-	// Collision detection and response between zombie and player
-	if collision := g.Player.Object.Check(0, 0, tagMob); collision != nil {
-		if g.Player.Object.Overlaps(collision.Objects[0]) {
-			if g.Player.Object.Shape.Intersection(0, 0, collision.Objects[0].Shape) != nil {
-				g.Music.Pause()
-				g.Sounds[soundPlayerDies].Play()
-				g.Stat.CounterPlayerDied++
-				return gameOver, nil // return early, no point in continuing, you are dead
-			}
-		}
+	// Game over if zombies have taken all the player's health
+	if g.Player.Health <= 0 {
+		g.Music.Pause()
+		g.Sounds[soundPlayerDies].Play()
+		g.Stat.CounterPlayerDied++
+		return gameOver, nil // return early, no point in continuing, you are dead
 	}
 
 	// Do something special when you find a Checkpoint entity
@@ -479,6 +556,7 @@ func (g *GameScreen) Update() (GameState, error) {
 	if g.BossDefeated {
 		if collision := g.Player.Object.Check(0, 0, tagEnd); collision != nil {
 			if g.Player.Object.Overlaps(collision.Objects[0]) {
+				g.Stat.DogTrust = g.Dog.Trust
 				return gameWon, nil
 			}
 		}
@@ -492,13 +570,6 @@ func (g *GameScreen) Update() (GameState, error) {
 		}
 	}
 
-	// Collision detection and response between zombie and dog
-	if collision := g.Dog.Object.Check(0, 0, tagMob); collision != nil {
-		if g.Dog.Object.Overlaps(collision.Objects[0]) {
-			g.Dog.Mode = dogDead
-		}
-	}
-
 	// Game over if the dog dies
 	if g.Dog.Mode == dogDead {
 		g.Stat.CounterDogDied++
@@ -535,6 +606,9 @@ func (g *GameScreen) Draw(screen *ebiten.Image) {
 		g.Camera.GetTranslation(&ebiten.DrawImageOptions{}, 0, 0),
 	)
 
+	// Things lying on the ground
+	g.Pickups.Draw(g)
+
 	// Dog
 	g.Dog.Draw(g)
 
@@ -544,15 +618,29 @@ func (g *GameScreen) Draw(screen *ebiten.Image) {
 	// Zombies
 	g.Zombies.Draw(g)
 
+	// Bullets in flight
+	g.Projectiles.Draw(g)
+
+	// Flares, bottles and the light from the flares
+	g.Throwables.Draw(g)
+
 	// Tree tops etc. high-up stuff need to be drawn above the entities
 	g.Camera.Surface.DrawImage(
 		g.Foreground,
 		g.Camera.GetTranslation(&ebiten.DrawImageOptions{}, 0, 0),
 	)
 
+	// Dog hiding under the trees
+	g.Dog.DrawHidden(g)
+
+	// Boss arena boundary
+	if g.Encounter != nil {
+		g.Encounter.Draw(g)
+	}
+
 	g.Camera.Blit(screen)
 
-	g.HUD.Draw(g.Player.Ammo, screen)
+	g.HUD.Draw(g, screen)
 
 	if g.Player.State != playerReload {
 		g.Cursor.Draw(screen)
@@ -585,6 +673,10 @@ func Shoot(g *GameScreen) {
 		g.Stat.CounterDryFires++
 	}
 
+	if g.Player.Staggered > 0 {
+		return // can't aim while staggered
+	}
+
 	switch g.Player.State {
 	case playerShooting, playerReady, playerUnready:
 		return // no-op
@@ -592,41 +684,57 @@ func Shoot(g *GameScreen) {
 		interruptReload()
 		return
 	default:
-		if g.Player.Ammo < 1 {
+		gun := g.Player.Gun()
+		if g.Player.Cooldown > 0 {
+			return // the gun isn't ready to fire again yet
+		}
+		if gun.Ammo < 1 {
 			interruptReload()
 			return
 		}
 
 		g.Sounds[soundGunShot].Play()
+		g.MakeNoise(*g.Player.Position(), playerGunNoiseRadius, g.Player.Object)
 
 		g.Stat.CounterBulletsFired++
-		g.Player.Ammo--
+		gun.Ammo--
+		g.Player.Cooldown = gun.FireRate
 		g.Player.State = playerShooting
-		rangeOfFire := g.Player.Range
-		sX, sY := g.Space.WorldToSpace(
-			g.Player.Object.X-math.Cos(g.Player.Angle-math.Pi)*rangeOfFire,
-			g.Player.Object.Y-math.Sin(g.Player.Angle-math.Pi)*rangeOfFire,
-		)
-		pX, pY := g.Space.WorldToSpace(
-			g.Player.Object.X+g.Player.Object.W/2,
-			g.Player.Object.Y+g.Player.Object.H/2,
-		)
-		cells := g.Space.CellsInLine(pX, pY, sX, sY)
-		for _, c := range cells {
-			for _, o := range c.Objects {
-				if o.HasTags(tagMob) {
-					log.Println("HIT!")
-					g.Cursor.Hit = true
-					o.Data.(*Zombie).Hit(g)
-					return // stop at the first zombie
-				} else {
-					g.Cursor.Hit = false
-				}
+		g.Player.recoil(gun.Recoil)
+		g.Shots = g.Shots[:0]
+		if gun.Speed > 0 {
+			// The shots are recorded when the bullets stop flying
+			for i := 0; i < gun.Pellets; i++ {
+				g.Projectiles = append(g.Projectiles, NewProjectile(gun, *g.Player.Position(), g.Player.shotAngle(), g.Player.Range()))
 			}
+			return
+		}
+		hit := false
+		for i := 0; i < gun.Pellets; i++ {
+			shot := g.fireBullet(gun, g.Player.shotAngle())
+			if shot.Hit {
+				log.Println("HIT!")
+				hit = true
+			}
+			g.Shots = append(g.Shots, shot)
 		}
+		g.Cursor.ShowResult(hit)
 	}
 }
 
+// isOnScreen returns whether the world coordinates are visible on the screen
+func (g *GameScreen) isOnScreen(x, y float64) bool {
+	sx, sy := g.Camera.GetScreenCoords(x, y)
+	return sx >= 0 && sy >= 0 && sx <= float64(g.Width) && sy <= float64(g.Height)
+}
+
+// surfaceCoords converts world coordinates into coordinates on the camera
+// surface, for drawing shapes that don't go through DrawImageOptions
+func (g *GameScreen) surfaceCoords(x, y float64) (float64, float64) {
+	w, h := g.Camera.Surface.Size()
+	return x - g.Camera.X + float64(w)/2, y - g.Camera.Y + float64(h)/2
+}
+
 // CalcObjectDistance calculates the distance between two Objects
 func CalcObjectDistance(obj1, obj2 *Coord) (float64, float64, float64) {
 	return CalcDistance(obj1.X, obj1.Y, obj2.X, obj2.Y), obj1.X - obj2.X, obj1.Y - obj2.Y
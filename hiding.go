@@ -0,0 +1,120 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"math"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+// dogHideRadius is how far the dog goes to hide under trees when zombies come
+var dogHideRadius float64 = 128
+
+// dogHiddenSightRadius is how close a zombie has to get to see the dog
+// hiding under the trees
+var dogHiddenSightRadius float64 = 40
+
+// dogHiddenAlpha is how much of the hiding dog shows through the trees
+var dogHiddenAlpha float64 = 0.35
+
+// Cover are the free tiles under the tree tops where the dog can hide
+type Cover map[image.Point]bool
+
+// NewCover finds the free tiles of the map which are under the tiles of
+// the tree tops layer
+func NewCover(layer *ldtkgo.Layer, m LevelMap) Cover {
+	cover := Cover{}
+	size := layer.GridSize
+	if layer.Tileset != nil {
+		size = layer.Tileset.GridSize
+	}
+	for _, t := range layer.AllTiles() {
+		x0, y0 := t.Position[0]+layer.OffsetX, t.Position[1]+layer.OffsetY
+		for y := y0 / gridSize; y < (y0+size)/gridSize; y++ {
+			for x := x0 / gridSize; x < (x0+size)/gridSize; x++ {
+				if p := image.Pt(x, y); m.isFreeAt(p) {
+					cover[p] = true
+				}
+			}
+		}
+	}
+	return cover
+}
+
+// findCover looks for the best hiding place under the trees the dog can
+// get to, it returns false if there isn't one close enough
+func (d *Dog) findCover(g *GameScreen, vector Coord) (*Path, bool) {
+	if len(g.Cover) == 0 {
+		return nil, false
+	}
+	start := tileAt(*d.Position())
+	from := g.LevelMap.Reachable(start, int(dogHideRadius/gridSize))
+
+	var zombies []*Coord
+	for _, z := range g.Zombies {
+		zombies = append(zombies, z.Position())
+	}
+
+	away := safeNormalize(vector)
+	best, bestScore := start, math.Inf(-1)
+	for tile := range from {
+		if !g.Cover[tile] {
+			continue
+		}
+		if score := d.fleeScore(g, tileCentre(tile), away, zombies); score > bestScore {
+			best, bestScore = tile, score
+		}
+	}
+	if math.IsInf(bestScore, -1) {
+		return nil, false
+	}
+
+	// Don't hide where a zombie would see the dog right away
+	c := tileCentre(best)
+	for _, z := range zombies {
+		if CalcDistance(c.X, c.Y, z.X, z.Y) < dogHiddenSightRadius {
+			return nil, false
+		}
+	}
+
+	return &Path{Points: PathFrom(from, best)}, true
+}
+
+// hidden returns whether the dog has made it under the trees and is hiding
+func (d *Dog) hidden() bool {
+	return d.State == dogDangerHiding && d.CurrentPath.NextPoint == len(d.CurrentPath.Points)
+}
+
+// spotted returns whether a zombie has come close enough to see the dog in
+// its hiding place
+func (d *Dog) spotted(g *GameScreen) bool {
+	for _, z := range g.Zombies {
+		if d.seenHidingFrom(g, *z.Position()) {
+			return true
+		}
+	}
+	return false
+}
+
+// canSeeDog returns whether the zombie can see the dog, it can't see it
+// hiding under the trees unless it is really close
+func (z *Zombie) canSeeDog(g *GameScreen) bool {
+	if !g.Dog.hidden() {
+		return true
+	}
+	return g.Dog.seenHidingFrom(g, *z.Position())
+}
+
+// seenHidingFrom returns whether the dog hiding under the trees can be seen
+// from the position, which has to be really close with no wall in the way
+func (d *Dog) seenHidingFrom(g *GameScreen, from Coord) bool {
+	distance := CalcDistance(from.X, from.Y, d.Object.X, d.Object.Y)
+	if distance >= dogHiddenSightRadius {
+		return false
+	}
+	angle := math.Atan2(d.Object.Y-from.Y, d.Object.X-from.X)
+	return len(g.castRay(from, angle, distance, tagWall)) == 0
+}
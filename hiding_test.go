@@ -0,0 +1,43 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"testing"
+
+	"github.com/solarlune/ldtkgo"
+)
+
+func TestNewCover(t *testing.T) {
+	// One 96px tree top covering 3x3 tiles, with a trunk in the middle
+	m := CreateMap(5, 5)
+	m.SetObstacle(2, 2)
+	layer := &ldtkgo.Layer{
+		GridSize: gridSize,
+		Tileset:  &ldtkgo.Tileset{GridSize: 96},
+		Tiles:    []*ldtkgo.Tile{{Position: []int{32, 32}}},
+	}
+
+	cover := NewCover(layer, m)
+
+	tests := []struct {
+		tile image.Point
+		want bool
+	}{
+		{image.Pt(1, 1), true},
+		{image.Pt(3, 3), true},
+		{image.Pt(2, 2), false}, // trunk
+		{image.Pt(0, 0), false}, // outside the tree
+		{image.Pt(4, 2), false}, // outside the tree
+	}
+	for _, tt := range tests {
+		if got := cover[tt.tile]; got != tt.want {
+			t.Errorf("cover at %v is %v, want %v", tt.tile, got, tt.want)
+		}
+	}
+	if len(cover) != 8 {
+		t.Errorf("got %d cover tiles, want 8", len(cover))
+	}
+}
@@ -4,7 +4,13 @@
 package main
 
 import (
+	"image/color"
+	"math"
+	"strconv"
+
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/tinne26/etxt"
 )
 
 // HudImage are images for use in the HUD
@@ -13,14 +19,39 @@ type HudImage int
 const (
 	hudBullet HudImage = iota
 	hudCasing
+	hudArrow
 )
 
 var hudPadding int = 5
 
-// HUD is a display showing information during the game
-// So far it only shows how much ammo you have left
+// Size of the health bars in the HUD
+const (
+	hudBarWidth  = 60
+	hudBarHeight = 4
+)
+
+// Colours of the bars in the HUD
+var (
+	hudBarBackground = color.RGBA{0x20, 0x20, 0x20, 0xc0}
+	hudHealthColour  = color.RGBA{0xc8, 0x44, 0x13, 0xff}
+	hudDogColour     = color.RGBA{0xd8, 0xa0, 0x50, 0xff}
+	hudStaminaColour = color.RGBA{0x6a, 0xa8, 0x4f, 0xff}
+	hudWindedColour  = color.RGBA{0x60, 0x60, 0x60, 0xff}
+	hudBossColour    = color.RGBA{0x8a, 0x10, 0x10, 0xff}
+)
+
+// hudBossBarWidth is the width of the boss health bar across the top
+const hudBossBarWidth = 160
+
+// hudArrowMargin is how far from the edge of the screen the arrow pointing
+// at the dog is drawn
+const hudArrowMargin = 16
+
+// HUD is a display showing information during the game, like how much ammo
+// and health you have left
 type HUD struct {
 	Images []*ebiten.Image
+	Text   *etxt.Renderer
 }
 
 // NewHUD initialises a new HUD with its graphics
@@ -29,28 +60,161 @@ func NewHUD() *HUD {
 		Images: []*ebiten.Image{
 			loadImage("assets/sprites/Bullet.png"),
 			loadImage("assets/sprites/Casing.png"),
+			loadImage("assets/sprites/Arrow.png"),
 		},
+		Text: NewHUDTextRenderer(),
 	}
 }
 
+// NewHUDTextRenderer creates a text renderer for the numbers in the HUD
+func NewHUDTextRenderer() *etxt.Renderer {
+	r := etxt.NewStdRenderer()
+	r.SetFont(loadFont("assets/fonts/PixelOperator8-Bold.ttf"))
+	r.SetAlign(etxt.Bottom, etxt.Right)
+	r.SetSizePx(8)
+	return r
+}
+
 // Draw draws the HUD onto the screen, this is intended to be drawn onto the
 // game screen with current camera view *after* the camera surface has been
 // blitted to the screen
-func (hud HUD) Draw(ammo int, screen *ebiten.Image) {
+func (hud HUD) Draw(g *GameScreen, screen *ebiten.Image) {
 	corner := screen.Bounds().Max
+	gun := g.Player.Gun()
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(
 		float64(corner.X),
-		float64(corner.Y-hud.Images[hudBullet].Bounds().Dy()-hudPadding),
+		float64(corner.Y-gun.Bullet.Bounds().Dy()-hudPadding),
 	)
-	for i := 0; i < playerAmmoClipMax; i++ {
+	for i := 0; i < gun.Clip; i++ {
 		var bullet *ebiten.Image
-		if i < ammo {
-			bullet = hud.Images[hudBullet]
+		if i < gun.Ammo {
+			bullet = gun.Bullet
 		} else {
-			bullet = hud.Images[hudCasing]
+			bullet = gun.Casing
 		}
 		op.GeoM.Translate(float64(-bullet.Bounds().Dx()-hudPadding), 0)
 		screen.DrawImage(bullet, op)
 	}
+
+	// Spare rounds next to the clip, in red when there are none left
+	hud.Text.SetColor(color.White)
+	if gun.Reserve == 0 {
+		hud.Text.SetColor(hudHealthColour)
+	}
+	hud.Text.SetTarget(screen)
+	hud.Text.Draw(
+		strconv.Itoa(gun.Reserve),
+		int(op.GeoM.Element(0, 2))-hudPadding,
+		corner.Y-hudPadding,
+	)
+
+	// The throwable ready to throw and how many are left, next to the bars
+	p := g.Player
+	hud.Text.SetColor(color.White)
+	if p.Throwables[p.Throwable] == 0 {
+		hud.Text.SetColor(hudHealthColour)
+	}
+	hud.Text.SetAlign(etxt.Bottom, etxt.Left)
+	hud.Text.Draw(
+		throwableNames[p.Throwable]+" "+strconv.Itoa(p.Throwables[p.Throwable]),
+		hudPadding*2+hudBarWidth,
+		corner.Y-hudPadding,
+	)
+	hud.Text.SetAlign(etxt.Bottom, etxt.Right)
+
+	hud.drawBar(
+		screen,
+		float64(hudPadding), float64(corner.Y-hudPadding-hudBarHeight),
+		float64(g.Player.Health)/float64(playerMaxHealth),
+		hudHealthColour,
+	)
+	hud.drawBar(
+		screen,
+		float64(hudPadding), float64(corner.Y-hudPadding*2-hudBarHeight*2),
+		g.Dog.Health/dogMaxHealth,
+		hudDogColour,
+	)
+
+	// Stamina turns grey when the player is out of breath
+	staminaColour := hudStaminaColour
+	if g.Player.Winded {
+		staminaColour = hudWindedColour
+	}
+	hud.drawBar(
+		screen,
+		float64(hudPadding), float64(corner.Y-hudPadding*3-hudBarHeight*3),
+		g.Player.Stamina,
+		staminaColour,
+	)
+
+	hud.drawDogIndicator(g, screen)
+
+	// Boss health while fighting a boss
+	if e := g.Encounter; e != nil && e.Locked {
+		x := float64(corner.X-hudBossBarWidth) / 2
+		y := float64(hudPadding * 2)
+		ebitenutil.DrawRect(screen, x, y, hudBossBarWidth, hudBarHeight, hudBarBackground)
+		ebitenutil.DrawRect(screen, x, y, hudBossBarWidth*math.Max(0, e.Boss.Health()), hudBarHeight, hudBossColour)
+	}
+}
+
+// drawDogIndicator draws an arrow at the edge of the screen pointing at the
+// dog when it is out of sight, and over its head when it barks at zombies.
+// The arrow turns red and pulses faster the closer the dog is to getting lost.
+func (hud HUD) drawDogIndicator(g *GameScreen, screen *ebiten.Image) {
+	d := g.Dog
+	if d.Mode == dogDead {
+		return
+	}
+
+	// Where the dog is on the screen, the same way the camera is blitted
+	x, y := g.surfaceCoords(d.Object.X, d.Object.Y)
+	x, y = x*g.Camera.Scale, y*g.Camera.Scale
+	corner := screen.Bounds().Max
+	w, h := float64(corner.X), float64(corner.Y)
+	onScreen := x >= 0 && y >= 0 && x <= w && y <= h
+	barking := d.State == dogDangerBarking
+
+	var angle float64
+	urgency := math.Min(1, float64(d.OutOfSightCounter)/float64(outOfSightLimit))
+	switch {
+	case onScreen && barking:
+		// Point down at the dog from above its head
+		y -= 24
+		angle = math.Pi
+		urgency = 1
+	case onScreen:
+		return
+	default:
+		// Point from the middle of the screen towards the dog, on the edge
+		dx, dy := x-w/2, y-h/2
+		scale := math.Min(
+			(w/2-hudArrowMargin)/math.Abs(dx),
+			(h/2-hudArrowMargin)/math.Abs(dy),
+		)
+		x, y = w/2+dx*scale, h/2+dy*scale
+		angle = math.Atan2(dy, dx) + math.Pi/2
+		if barking {
+			urgency = 1
+		}
+	}
+
+	arrow := hud.Images[hudArrow]
+	aw, ah := arrow.Size()
+	pulse := 1 + 0.3*urgency*math.Abs(math.Sin(float64(g.Tick)*(0.05+0.2*urgency)))
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-float64(aw)/2, -float64(ah)/2)
+	op.GeoM.Scale(pulse, pulse)
+	op.GeoM.Rotate(angle)
+	op.GeoM.Translate(x, y)
+	op.ColorM.Scale(1, 1-urgency, 1-urgency, 1)
+	screen.DrawImage(arrow, op)
+}
+
+// drawBar draws a horizontal bar filled up to the given fraction
+func (hud HUD) drawBar(screen *ebiten.Image, x, y, fraction float64, clr color.Color) {
+	fraction = math.Max(0, math.Min(1, fraction))
+	ebitenutil.DrawRect(screen, x, y, hudBarWidth, hudBarHeight, hudBarBackground)
+	ebitenutil.DrawRect(screen, x, y, hudBarWidth*fraction, hudBarHeight, clr)
 }
@@ -66,15 +66,18 @@ func (m LevelMap) Neighbours(p image.Point) []image.Point {
 	return neighbours
 }
 
-// isFreeAt returns if the tile is free
+// isFreeAt returns if the tile is free, tiles outside the map are never free
 func (m LevelMap) isFreeAt(p image.Point) bool {
+	if p.Y < 0 || p.Y >= len(m) || p.X < 0 || p.X >= len(m[p.Y]) {
+		return false
+	}
 	return m[p.Y][p.X] == 0
 }
 
 // isFreeAtCoord returns if the tile under the coordinate is free
 func (m LevelMap) isFreeAtCoord(c Coord) bool {
-	p := image.Pt(int(c.X/gridSize), int(c.Y/gridSize))
-	return m[p.Y][p.X] == 0
+	p := image.Pt(int(math.Floor(c.X/gridSize)), int(math.Floor(c.Y/gridSize)))
+	return m.isFreeAt(p)
 }
 
 // distance calculates Euclidean distance between the points
@@ -103,6 +106,54 @@ func (m LevelMap) FindPath(start, dest Coord) []Coord {
 	return result
 }
 
+// Reachable finds the tiles that can be reached from the start tile in at
+// most maxSteps steps, it returns the tile each of them was reached from so
+// the path to any of them can be followed back to the start
+func (m LevelMap) Reachable(start image.Point, maxSteps int) map[image.Point]image.Point {
+	from := map[image.Point]image.Point{start: start}
+	frontier := []image.Point{start}
+	for step := 0; step < maxSteps && len(frontier) > 0; step++ {
+		var next []image.Point
+		for _, p := range frontier {
+			for _, q := range m.Neighbours(p) {
+				if _, seen := from[q]; !seen {
+					from[q] = p
+					next = append(next, q)
+				}
+			}
+		}
+		frontier = next
+	}
+	return from
+}
+
+// PathFrom follows the tiles found by Reachable back from the destination
+// and returns the path from the start to it as coordinates
+func PathFrom(from map[image.Point]image.Point, dest image.Point) []Coord {
+	var tiles []image.Point
+	for p := dest; ; p = from[p] {
+		tiles = append([]image.Point{p}, tiles...)
+		if from[p] == p {
+			break
+		}
+	}
+	var result []Coord
+	for _, p := range simplifyPath(tiles) {
+		result = append(result, tileCentre(p))
+	}
+	return result
+}
+
+// tileCentre returns the coordinates of the centre of the tile
+func tileCentre(p image.Point) Coord {
+	return Coord{X: (float64(p.X) + 0.5) * gridSize, Y: (float64(p.Y) + 0.5) * gridSize}
+}
+
+// tileAt returns the tile under the coordinates
+func tileAt(c Coord) image.Point {
+	return image.Pt(int(math.Floor(c.X/gridSize)), int(math.Floor(c.Y/gridSize)))
+}
+
 // simplifyPath removes unnecessary points from the path
 func simplifyPath(path []image.Point) []image.Point {
 	var result []image.Point
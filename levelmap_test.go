@@ -0,0 +1,46 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+func TestReachable(t *testing.T) {
+	// A wall splits the map, with a gap at the bottom
+	//  . # .
+	//  . # .
+	//  . . .
+	m := CreateMap(3, 3)
+	m.SetObstacle(1, 0)
+	m.SetObstacle(1, 1)
+
+	tests := []struct {
+		steps int
+		tile  image.Point
+		want  bool
+	}{
+		{0, image.Pt(0, 0), true},
+		{1, image.Pt(0, 1), true},
+		{1, image.Pt(1, 0), false}, // wall
+		{5, image.Pt(2, 0), false}, // too far around the wall
+		{6, image.Pt(2, 0), true},
+	}
+	for _, tt := range tests {
+		from := m.Reachable(image.Pt(0, 0), tt.steps)
+		if _, got := from[tt.tile]; got != tt.want {
+			t.Errorf("Reachable in %d steps to %v is %v, want %v", tt.steps, tt.tile, got, tt.want)
+		}
+	}
+
+	from := m.Reachable(image.Pt(0, 0), 10)
+	path := PathFrom(from, image.Pt(2, 0))
+	if first := path[0]; first != tileCentre(image.Pt(0, 0)) {
+		t.Errorf("Path starts at %v, want the start tile", first)
+	}
+	if last := path[len(path)-1]; last != tileCentre(image.Pt(2, 0)) {
+		t.Errorf("Path ends at %v, want the destination tile", last)
+	}
+}
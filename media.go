@@ -69,19 +69,24 @@ type SpriteSheet struct {
 	Image  *ebiten.Image
 }
 
+// FrameTag finds the frame tag with the given name
+func (ss *SpriteSheet) FrameTag(name string) (FrameTags, bool) {
+	for _, tag := range ss.Meta.FrameTags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return FrameTags{}, false
+}
+
 // SpriteType is a unique identifier to load a sprite by name
 type SpriteType uint64
 
 const (
 	spritePlayer SpriteType = iota
 	spriteDog
-	spriteZombieSprinter
-	spriteZombieBig
-	spriteZombieCrawler
 )
 
-const zombieVariants = 4
-
 // Load a sprite image and associated meta-data given a file name (without
 // extension)
 func loadSprite(name string) *SpriteSheet {
@@ -176,13 +181,7 @@ const (
 	soundPlayerDies
 	soundHit
 	soundDryFire
-	soundZombieScream
-	soundZombieGrowl
-	soundZombieDeath
-	soundBigZombieSound
-	soundBigZombieDeath1
-	soundBigZombieScream
-	soundBigZombieDeath2
+	soundDogSniff
 )
 
 const (
@@ -0,0 +1,63 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/solarlune/resolv"
+)
+
+// playerGunNoiseRadius is how far away zombies can hear a gunshot
+var playerGunNoiseRadius float64 = 320
+
+// noiseTime is how long (ticks) zombies can still pick up a noise after it was made
+const noiseTime = 30
+
+// Noise is a sound zombies can hear, it draws them towards whatever made it
+type Noise struct {
+	Position Coord          // Where the noise was made
+	Radius   float64        // How far away the noise can be heard
+	Source   *resolv.Object // What made the noise, zombies hearing it go after it
+	Time     int            // Ticks left until the noise is gone
+}
+
+// Noises is an array of Noise
+type Noises []*Noise
+
+// MakeNoise makes a noise which zombies within the radius hear
+func (g *GameScreen) MakeNoise(position Coord, radius float64, source *resolv.Object) {
+	g.Noises = append(g.Noises, &Noise{
+		Position: position,
+		Radius:   radius,
+		Source:   source,
+		Time:     noiseTime,
+	})
+}
+
+// Update fades out the noises and removes the ones that are gone
+func (ns *Noises) Update() {
+	kept := (*ns)[:0]
+	for _, n := range *ns {
+		n.Time--
+		if n.Time > 0 {
+			kept = append(kept, n)
+		}
+	}
+	*ns = kept
+}
+
+// Loudness returns how loud the noises made by the source are at the
+// position, from 0 for not heard at all to 1 for right next to it
+func (ns Noises) Loudness(position Coord, source *resolv.Object) float64 {
+	loudest := 0.0
+	for _, n := range ns {
+		if n.Source != source {
+			continue
+		}
+		d := CalcDistance(position.X, position.Y, n.Position.X, n.Position.Y)
+		if loudness := 1 - d/n.Radius; loudness > loudest {
+			loudest = loudness
+		}
+	}
+	return loudest
+}
@@ -0,0 +1,148 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/solarlune/ldtkgo"
+)
+
+// pickupHealthAmount is how much health one health pickup gives the player,
+// how much ammo an ammo pickup gives is set for each weapon
+var pickupHealthAmount int = 25
+
+// pickupRadius is how close the player has to get to pick something up
+const pickupRadius = 16
+
+// pickupHealthColour is the colour of the cross on health pickups
+var pickupHealthColour = color.RGBA{0xc8, 0x44, 0x13, 0xff}
+
+// Kinds of pickups
+const (
+	pickupAmmo = iota
+	pickupHealth
+	pickupThrowable
+)
+
+// Pickup is something lying around which the player can pick up by walking
+// over it
+type Pickup struct {
+	Kind      int    // What the player gets
+	Weapon    string // Name of the weapon ammo is for, empty for the gun the player holds
+	Amount    int    // How much of it the player gets, 0 for the default amount
+	Position  Coord  // Where the pickup is lying
+	Collected bool   // Whether the player has picked it up already
+}
+
+// Pickups is an array of Pickup
+type Pickups []*Pickup
+
+// DropPickup leaves a pickup on the ground, ammo is for whichever gun the
+// player holds when they pick it up
+func (g *GameScreen) DropPickup(kind int, position Coord) {
+	g.Pickups = append(g.Pickups, &Pickup{
+		Kind:     kind,
+		Position: position,
+	})
+}
+
+// NewMapPickup creates a pickup from an entity placed on the map
+func NewMapPickup(e *ldtkgo.Entity) *Pickup {
+	p := &Pickup{
+		Kind: pickupAmmo,
+		Position: Coord{
+			X: float64(e.Position[0] + e.Width/2),
+			Y: float64(e.Position[1] + e.Height/2),
+		},
+	}
+	if weapon := e.PropertyByIdentifier("Weapon"); weapon != nil && !weapon.IsNull() {
+		p.Weapon = weapon.AsString()
+	}
+	if amount := e.PropertyByIdentifier("Amount"); amount != nil && !amount.IsNull() {
+		p.Amount = amount.AsInt()
+	}
+	return p
+}
+
+// Remaining returns the pickups the player hasn't picked up yet
+func (ps Pickups) Remaining() Pickups {
+	remaining := Pickups{}
+	for _, p := range ps {
+		if !p.Collected {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
+
+// Update gives the player the pickups they walk over
+func (ps *Pickups) Update(g *GameScreen) {
+	kept := (*ps)[:0]
+	for _, p := range *ps {
+		if CalcDistance(p.Position.X, p.Position.Y, g.Player.Object.X, g.Player.Object.Y) < pickupRadius {
+			p.PickUp(g)
+			p.Collected = true
+			continue
+		}
+		kept = append(kept, p)
+	}
+	*ps = kept
+}
+
+// PickUp gives the player what the pickup holds
+func (p *Pickup) PickUp(g *GameScreen) {
+	switch p.Kind {
+	case pickupAmmo:
+		gun := g.Player.GunNamed(p.Weapon)
+		amount := gun.Pickup
+		if p.Amount > 0 {
+			amount = p.Amount
+		}
+		gun.Reserve += amount
+		g.Sounds[soundGunReload].Play()
+	case pickupHealth:
+		amount := pickupHealthAmount
+		if p.Amount > 0 {
+			amount = p.Amount
+		}
+		g.Player.Health = int(math.Min(float64(playerMaxHealth), float64(g.Player.Health+amount)))
+	case pickupThrowable:
+		amount := 1
+		if p.Amount > 0 {
+			amount = p.Amount
+		}
+		g.Player.Throwables[g.Player.Throwable] += amount
+	}
+}
+
+// Draw draws the pickups lying on the ground, bobbing up and down a bit so
+// they catch the eye
+func (ps Pickups) Draw(g *GameScreen) {
+	bob := 2 * math.Sin(2*math.Pi*float64(g.Tick)/60)
+	for _, p := range ps {
+		switch p.Kind {
+		case pickupAmmo:
+			bullet := g.HUD.Images[hudBullet]
+			op := &ebiten.DrawImageOptions{}
+			w, h := bullet.Size()
+			for i := -1; i <= 1; i++ {
+				op.GeoM.Reset()
+				op.GeoM.Translate(float64(-w/2+i*(w+1)), float64(-h/2)+bob)
+				g.Camera.Surface.DrawImage(bullet, g.Camera.GetTranslation(op, p.Position.X, p.Position.Y))
+			}
+		case pickupHealth:
+			x, y := g.surfaceCoords(p.Position.X, p.Position.Y+bob)
+			ebitenutil.DrawRect(g.Camera.Surface, x-5, y-2, 10, 4, pickupHealthColour)
+			ebitenutil.DrawRect(g.Camera.Surface, x-2, y-5, 4, 10, pickupHealthColour)
+		case pickupThrowable:
+			x, y := g.surfaceCoords(p.Position.X, p.Position.Y+bob)
+			ebitenutil.DrawRect(g.Camera.Surface, x-3, y-2, 2, 4, flareColour)
+			ebitenutil.DrawRect(g.Camera.Surface, x+1, y-3, 2, 5, bottleColour)
+		}
+	}
+}
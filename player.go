@@ -23,7 +23,17 @@ var playerSpeedFactorSideways float64 = 0.6
 
 var playerSpeedFactorSprint float64 = 2.4
 
-var playerAmmoClipMax int = 7
+// playerMaxHealth is how much health the player starts with
+var playerMaxHealth int = 100
+
+// playerInvulnerableTime is how long (ticks) the player can't be hurt after a hit
+var playerInvulnerableTime int = 60
+
+// playerKnockbackSpeed is how fast the player is pushed away when hit
+var playerKnockbackSpeed float64 = 3
+
+// playerKnockbackFriction is how much of the knockback speed is kept each tick
+const playerKnockbackFriction = 0.8
 
 // states of the player
 // It would be great to map them to the frameTag.Name from JSON
@@ -41,20 +51,33 @@ const (
 
 // Player is the player character in the game
 type Player struct {
-	Object    *resolv.Object // Used for collision detection with other objects
-	Angle     float64        // The angle the player is facing at
-	Frame     int            // The current animation frame
-	State     playerState    // The current animation state
-	PrevState playerState    // The previous animation state
-	Sprinting bool           // Whether the player is sprinting or not
-	Sprite    *SpriteSheet   // Used for player animations
-	Range     float64        // How far you can shoot with the gun
-	Ammo      int            // How many shots you have left in the gun
-	TempSpeed float64        // Temporary speed multiplier
+	Object        *resolv.Object         // Used for collision detection with other objects
+	Angle         float64                // The angle the player is facing at
+	Frame         int                    // The current animation frame
+	State         playerState            // The current animation state
+	PrevState     playerState            // The previous animation state
+	Sprinting     bool                   // Whether the player is sprinting or not
+	Stamina       float64                // How much longer the player can sprint, from 0 to 1
+	Winded        bool                   // Whether the player ran out of stamina and can't sprint
+	Aiming        bool                   // Whether the player is aiming down the sights
+	Bloom         float64                // Extra spread (radians) of the player's shots from moving and shooting
+	Throwables    [howManyThrowables]int // How many of each throwable the player carries
+	Throwable     int                    // Kind of throwable the player has ready to throw
+	Sprite        *SpriteSheet           // Used for player animations
+	Guns          []*Gun                 // The guns the player carries
+	CurrentGun    int                    // Index of the gun the player is holding
+	Cooldown      int                    // Ticks left until the gun can fire again
+	ShoveCooldown int                    // Ticks left until the player can shove again
+	Reloading     int                    // Ticks left until reloading is done
+	TempSpeed     float64                // Temporary speed multiplier
+	Health        int                    // How much more damage the player can take
+	Immunity      int                    // Ticks left until the player can be hurt again
+	Knockback     Coord                  // Velocity the player is being pushed with after a hit
+	Staggered     int                    // Ticks left until the player can move again
 }
 
 // NewPlayer constructs a new Player object at the provided location and size
-func NewPlayer(position []int, sprites *SpriteSheet) *Player {
+func NewPlayer(position []int, sprites *SpriteSheet, weapons []*Weapon) *Player {
 	// the head and shoulders are about 4px from the middle
 	const collisionBoxSize float64 = 8
 
@@ -75,29 +98,85 @@ func NewPlayer(position []int, sprites *SpriteSheet) *Player {
 		Object:    object,
 		Angle:     0,
 		Sprite:    sprites,
-		Range:     200,
-		Ammo:      playerAmmoClipMax,
+		Guns:      NewGuns(weapons),
 		TempSpeed: 1,
+		Health:    playerMaxHealth,
+		Stamina:   1,
 	}
+	player.Restock()
 
 	return player
 }
 
-// Reload reloads the ammo
+// Reload reloads the ammo, if there are spare rounds left for it
 func (p *Player) Reload(g *GameScreen) {
+	if !p.Gun().CanReload() {
+		return
+	}
 	p.State = playerReload
+	p.Reloading = p.Gun().ReloadTime
 	g.Sounds[soundGunReload].Play()
 }
 
+// Hurt damages the player unless they were hit very recently, and pushes
+// them away from where the hit came from
+func (p *Player) Hurt(g *GameScreen, damage int, from *Coord) {
+	if p.Immunity > 0 || p.Health <= 0 {
+		return
+	}
+	p.Health -= damage
+	p.Immunity = playerInvulnerableTime
+	g.Sounds[soundHit].Play()
+
+	push := safeNormalize(Coord{X: p.Object.X - from.X, Y: p.Object.Y - from.Y})
+	p.Knockback = Coord{X: push.X * playerKnockbackSpeed, Y: push.Y * playerKnockbackSpeed}
+}
+
+// Stagger stops the player from moving or shooting for a while
+func (p *Player) Stagger(ticks int) {
+	if ticks > p.Staggered {
+		p.Staggered = ticks
+	}
+}
+
 // Update updates the state of the player
 func (p *Player) Update(g *GameScreen) {
 	p.PrevState = p.State
 	p.Sprinting = false
 
-	if p.State == playerIdle || p.State == playerWalking {
+	if p.Immunity > 0 {
+		p.Immunity--
+	}
+	if p.Cooldown > 0 {
+		p.Cooldown--
+	}
+	if p.Reloading > 0 {
+		p.Reloading--
+	}
+	if p.ShoveCooldown > 0 {
+		p.ShoveCooldown--
+	}
+
+	// Being pushed back by a hit slowly wears off
+	if math.Abs(p.Knockback.X)+math.Abs(p.Knockback.Y) > 0.1 {
+		p.slide(p.Knockback.X, p.Knockback.Y)
+		p.Knockback.X *= playerKnockbackFriction
+		p.Knockback.Y *= playerKnockbackFriction
+	} else {
+		p.Knockback = Coord{}
+	}
+
+	p.steadyAim()
+	if p.Staggered > 0 {
+		p.Staggered--
+		if p.State == playerWalking {
+			p.State = playerIdle
+		}
+	} else if p.State == playerIdle || p.State == playerWalking {
 		p.State = playerIdle
 		p.handleControls()
 	}
+	p.updateStamina()
 
 	if p.Frame == p.Sprite.Meta.FrameTags[p.State].To {
 		p.animationBasedStateChanges(g)
@@ -119,11 +198,14 @@ func (p *Player) animationBasedStateChanges(g *GameScreen) {
 	switch p.State {
 	case playerShooting: // Back to idle after shooting animation
 		p.State = playerIdle
-		if p.Ammo < 1 {
+		if p.Gun().Ammo < 1 {
 			p.Reload(g) // Automatic reload if out of ammo
 		}
 	case playerReload: // Back to idle after reload animation
-		p.Ammo = playerAmmoClipMax
+		if p.Reloading > 0 {
+			return // keep going until the gun's reload time is up
+		}
+		p.Gun().Reload()
 		p.State = playerIdle
 	case playerDryFire: // Back to idle after reload animation
 		p.State = playerIdle
@@ -133,6 +215,7 @@ func (p *Player) animationBasedStateChanges(g *GameScreen) {
 // MoveLeft moves the player left
 func (p *Player) MoveLeft() {
 	speed := playerSpeed * playerSpeedFactorSideways * p.TempSpeed
+	p.disturbAim(playerSpreadStrafing)
 	p.move(
 		math.Sin(p.Angle)*speed,
 		-math.Cos(p.Angle)*speed,
@@ -142,6 +225,7 @@ func (p *Player) MoveLeft() {
 // MoveRight moves the player right
 func (p *Player) MoveRight() {
 	speed := playerSpeed * playerSpeedFactorSideways * p.TempSpeed
+	p.disturbAim(playerSpreadStrafing)
 	p.move(
 		-math.Sin(p.Angle)*speed,
 		math.Cos(p.Angle)*speed,
@@ -153,8 +237,11 @@ func (p *Player) MoveForward() {
 	speed := playerSpeed
 	if p.Sprinting && p.TempSpeed >= 1 {
 		speed = speed * playerSpeedFactorSprint
+		p.disturbAim(playerSpreadSprinting)
 	} else {
+		p.Sprinting = false
 		speed = speed * p.TempSpeed
+		p.disturbAim(playerSpreadWalking)
 	}
 	p.move(
 		math.Cos(p.Angle)*speed,
@@ -165,6 +252,7 @@ func (p *Player) MoveForward() {
 // MoveBackward moves the player backward away from the pointer
 func (p *Player) MoveBackward() {
 	speed := playerSpeed * playerSpeedFactorReverse * p.TempSpeed
+	p.disturbAim(playerSpreadWalking)
 	p.move(
 		-math.Cos(p.Angle)*speed,
 		-math.Sin(p.Angle)*speed,
@@ -174,6 +262,9 @@ func (p *Player) MoveBackward() {
 // Move the Player by the given vector if it is possible to do so
 func (p *Player) move(dx, dy float64) {
 	p.State = playerWalking
+	if p.Aiming {
+		dx, dy = dx*playerAimSpeedFactor, dy*playerAimSpeedFactor
+	}
 
 	// Collision detection and response between sand trap and player
 	p.TempSpeed = 1
@@ -183,6 +274,12 @@ func (p *Player) move(dx, dy float64) {
 		}
 	}
 
+	p.slide(dx, dy)
+}
+
+// Slide the Player by the given vector, stopping along each axis where it
+// would run into a wall or the dog
+func (p *Player) slide(dx, dy float64) {
 	if collision := p.Object.Check(dx, 0, tagWall, tagDog); collision != nil {
 		for _, o := range collision.Objects {
 			if p.Object.Shape.Intersection(dx, 0, o.Shape) != nil {
@@ -207,6 +304,11 @@ func (p *Player) Draw(g *GameScreen) {
 	// the centre of the player's head is 2px down from the middle
 	const centerOffset float64 = -2
 
+	// Blink while the player can't be hurt
+	if p.Immunity > 0 && (p.Immunity/4)%2 == 0 {
+		return
+	}
+
 	s := p.Sprite
 	frame := s.Sprite[p.Frame]
 	op := &ebiten.DrawImageOptions{}
@@ -234,7 +336,7 @@ func (p *Player) Draw(g *GameScreen) {
 }
 
 func (p *Player) handleControls() {
-	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+	if ebiten.IsKeyPressed(ebiten.KeyShift) && ebiten.IsKeyPressed(ebiten.KeyW) && !p.Winded && !p.Aiming {
 		p.Sprinting = true
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyW) {
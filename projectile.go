@@ -0,0 +1,111 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image/color"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/solarlune/resolv"
+)
+
+// projectileTracerLength is how many ticks of flight the tracer behind a
+// bullet shows
+const projectileTracerLength = 3
+
+// projectileTracerColour is the colour of the streak behind a flying bullet
+var projectileTracerColour = color.RGBA{0xff, 0xe0, 0x90, 0x80}
+
+// Projectile is a bullet flying through the air, for guns with bullets slow
+// enough to see
+type Projectile struct {
+	Gun       *Gun                    // The gun that fired it
+	From      Coord                   // Where the bullet was fired from
+	Position  Coord                   // Where the bullet is now
+	Angle     float64                 // The direction the bullet flies in
+	Travelled float64                 // How far the bullet has flown
+	Range     float64                 // How far the bullet can fly
+	Pierced   int                     // How many zombies it has gone through
+	Hit       map[*resolv.Object]bool // Zombies it has hit already
+	HitAny    bool                    // Whether it has hit any zombie
+	Stopped   bool                    // Whether the bullet has stopped flying
+}
+
+// Projectiles is an array of Projectile
+type Projectiles []*Projectile
+
+// NewProjectile fires a bullet from the gun in the given direction, which
+// flies as far as the range
+func NewProjectile(gun *Gun, from Coord, angle, length float64) *Projectile {
+	return &Projectile{
+		Gun:      gun,
+		From:     from,
+		Position: from,
+		Angle:    angle,
+		Range:    length,
+		Hit:      map[*resolv.Object]bool{},
+	}
+}
+
+// Update moves the bullet on by one tick of flight, hitting whatever is in
+// the way, and records the shot once the bullet stops
+func (p *Projectile) Update(g *GameScreen) {
+	step := math.Min(p.Gun.Speed, p.Range-p.Travelled)
+	distance, stopped, hit := g.bulletTravel(p.Gun, p.Position, p.Angle, step, &p.Pierced, p.Hit)
+	p.HitAny = p.HitAny || hit
+	p.Position.X += math.Cos(p.Angle) * distance
+	p.Position.Y += math.Sin(p.Angle) * distance
+	p.Travelled += distance
+	p.Stopped = stopped || p.Travelled >= p.Range
+	if !p.Stopped {
+		return
+	}
+
+	if p.HitAny {
+		log.Println("HIT!")
+	}
+	g.Shots = append(g.Shots, Shot{From: p.From, Impact: p.Position, Hit: p.HitAny})
+	g.Cursor.ShowResult(p.HitAny)
+}
+
+// Draw draws the bullet with a tracer streaking behind it
+func (p *Projectile) Draw(g *GameScreen) {
+	tail := math.Min(p.Travelled, p.Gun.Speed*projectileTracerLength)
+	x1, y1 := g.surfaceCoords(
+		p.Position.X-math.Cos(p.Angle)*tail,
+		p.Position.Y-math.Sin(p.Angle)*tail,
+	)
+	x2, y2 := g.surfaceCoords(p.Position.X, p.Position.Y)
+	ebitenutil.DrawLine(g.Camera.Surface, x1, y1, x2, y2, projectileTracerColour)
+
+	// The bullet images point up, and are drawn at half size
+	w, h := p.Gun.Bullet.Size()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(-float64(w)/2, -float64(h)/2)
+	op.GeoM.Scale(0.5, 0.5)
+	op.GeoM.Rotate(p.Angle + math.Pi/2)
+	g.Camera.Surface.DrawImage(p.Gun.Bullet, g.Camera.GetTranslation(op, p.Position.X, p.Position.Y))
+}
+
+// Update moves all the bullets in flight and drops the ones that stopped
+func (ps *Projectiles) Update(g *GameScreen) {
+	kept := (*ps)[:0]
+	for _, p := range *ps {
+		p.Update(g)
+		if !p.Stopped {
+			kept = append(kept, p)
+		}
+	}
+	*ps = kept
+}
+
+// Draw draws all the bullets in flight
+func (ps Projectiles) Draw(g *GameScreen) {
+	for _, p := range ps {
+		p.Draw(g)
+	}
+}
@@ -0,0 +1,99 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"sort"
+
+	"github.com/solarlune/resolv"
+)
+
+// RayHit is an object a ray runs into
+type RayHit struct {
+	Object   *resolv.Object // What the ray hit
+	Distance float64        // How far along the ray it hit
+}
+
+// castRay finds the objects with any of the tags whose collision shapes
+// the ray runs into before it reaches the given length, nearest first
+func (g *GameScreen) castRay(from Coord, angle, length float64, tags ...string) []RayHit {
+	dir := Coord{X: math.Cos(angle), Y: math.Sin(angle)}
+	sX, sY := g.Space.WorldToSpace(from.X, from.Y)
+	eX, eY := g.Space.WorldToSpace(from.X+dir.X*length, from.Y+dir.Y*length)
+
+	var hits []RayHit
+	seen := map[*resolv.Object]bool{}
+	for _, c := range g.Space.CellsInLine(sX, sY, eX, eY) {
+		for _, o := range c.Objects {
+			if seen[o] || !o.HasTags(tags...) {
+				continue
+			}
+			seen[o] = true
+			if distance, ok := rayPolygon(from, dir, shapePoints(o)); ok && distance <= length {
+				hits = append(hits, RayHit{Object: o, Distance: distance})
+			}
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Distance < hits[j].Distance })
+	return hits
+}
+
+// shapePoints returns the corners of the object's collision shape where
+// they are in the world, after rotating it
+func shapePoints(o *resolv.Object) []Coord {
+	polygon, ok := o.Shape.(*resolv.ConvexPolygon)
+	if !ok {
+		return []Coord{
+			{X: o.X, Y: o.Y},
+			{X: o.X + o.W, Y: o.Y},
+			{X: o.X + o.W, Y: o.Y + o.H},
+			{X: o.X, Y: o.Y + o.H},
+		}
+	}
+	var points []Coord
+	for _, p := range polygon.Transformed() {
+		points = append(points, Coord{X: p[0], Y: p[1]})
+	}
+	return points
+}
+
+// rayPolygon returns how far along the ray it first crosses an edge of the
+// polygon, a ray starting inside the polygon hits it straight away
+func rayPolygon(origin, dir Coord, points []Coord) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+
+	nearest, hit := math.Inf(1), false
+	left, right := false, false
+	for i := range points {
+		a, b := points[i], points[(i+1)%len(points)]
+		edge := Coord{X: b.X - a.X, Y: b.Y - a.Y}
+
+		// The origin is inside a convex polygon when it is on the same side of every edge
+		if side := edge.X*(origin.Y-a.Y) - edge.Y*(origin.X-a.X); side > 0 {
+			left = true
+		} else if side < 0 {
+			right = true
+		}
+
+		denominator := dir.X*edge.Y - dir.Y*edge.X
+		if denominator == 0 {
+			continue // parallel to the edge
+		}
+		toA := Coord{X: a.X - origin.X, Y: a.Y - origin.Y}
+		t := (toA.X*edge.Y - toA.Y*edge.X) / denominator // along the ray
+		u := (toA.X*dir.Y - toA.Y*dir.X) / denominator   // along the edge
+		if t >= 0 && u >= 0 && u <= 1 && t < nearest {
+			nearest, hit = t, true
+		}
+	}
+
+	if len(points) > 2 && left != right {
+		return 0, true
+	}
+	return nearest, hit
+}
@@ -0,0 +1,37 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRayPolygon(t *testing.T) {
+	// A square from (10,-5) to (20,5) and the same square turned 45°
+	square := []Coord{{10, -5}, {20, -5}, {20, 5}, {10, 5}}
+	diamond := []Coord{{15, -5}, {20, 0}, {15, 5}, {10, 0}}
+
+	tests := []struct {
+		name     string
+		origin   Coord
+		dir      Coord
+		points   []Coord
+		want     float64
+		wantsHit bool
+	}{
+		{"straight at it", Coord{0, 0}, Coord{1, 0}, square, 10, true},
+		{"away from it", Coord{0, 0}, Coord{-1, 0}, square, 0, false},
+		{"past it", Coord{0, 0}, Coord{0, 1}, square, 0, false},
+		{"at a corner", Coord{0, 0}, Coord{1, 0}, diamond, 10, true},
+		{"turned edge", Coord{15, -10}, Coord{0, 1}, diamond, 5, true},
+		{"from inside", Coord{15, 0}, Coord{1, 0}, square, 0, true},
+	}
+	for _, tt := range tests {
+		got, hit := rayPolygon(tt.origin, tt.dir, tt.points)
+		if hit != tt.wantsHit || (hit && math.Abs(got-tt.want) > 1e-9) {
+			t.Errorf("%s: got %v, %v want %v, %v", tt.name, got, hit, tt.want, tt.wantsHit)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/solarlune/resolv"
+)
+
+// How far in front of the player the shove reaches and how wide it is
+var (
+	playerShoveReach  float64 = 12
+	playerShoveRadius float64 = 12
+)
+
+// playerShoveSpeed is how fast zombies are pushed away by a shove
+var playerShoveSpeed float64 = 4
+
+// playerShoveCooldown is how long (ticks) the player has to wait before
+// shoving again
+var playerShoveCooldown int = 60
+
+// zombieStunTime is how long (ticks) a shoved zombie is stunned for
+var zombieStunTime int = 45
+
+// handleShove shoves the zombies in front of the player away with the space bar
+func (g *GameScreen) handleShove() {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.Player.Shove(g)
+	}
+}
+
+// Shove pushes away and stuns the zombies whose collision shapes are within
+// reach in front of the player
+func (p *Player) Shove(g *GameScreen) {
+	if p.ShoveCooldown > 0 || p.Staggered > 0 || p.Health <= 0 {
+		return
+	}
+	p.ShoveCooldown = playerShoveCooldown
+
+	area := resolv.NewCircle(
+		p.Object.X+math.Cos(p.Angle)*playerShoveReach,
+		p.Object.Y+math.Sin(p.Angle)*playerShoveReach,
+		playerShoveRadius,
+	)
+
+	shoved := false
+	for _, o := range g.Space.Objects() {
+		if !o.HasTags(tagMob) {
+			continue
+		}
+		// Shapes completely inside the circle don't cross its edge
+		if area.Intersection(0, 0, o.Shape) == nil && CalcDistance(area.X, area.Y, o.X, o.Y) > playerShoveRadius {
+			continue
+		}
+		o.Data.(Zombielike).Shove(g, *p.Position())
+		shoved = true
+	}
+	if shoved {
+		g.Sounds[soundHit].Play()
+	}
+}
+
+// Shove pushes the zombie away from where the shove came from and stuns it
+// for a while
+func (z *Zombie) Shove(g *GameScreen, from Coord) {
+	if z.State == zombieDeath || z.State == zombieDead {
+		return
+	}
+	push := safeNormalize(Coord{X: z.Object.X - from.X, Y: z.Object.Y - from.Y})
+	z.knockBack(Coord{X: push.X * playerShoveSpeed, Y: push.Y * playerShoveSpeed}, zombieStunTime)
+	z.State = zombieStunned
+}
+
+// Shove only staggers the boss in its first phase, after that it is too
+// strong to be pushed around
+func (z *Boss) Shove(g *GameScreen, from Coord) {
+	if z.Phase > 0 || len(z.Sequence) > 0 || z.Dead {
+		return
+	}
+	z.cancelAttack()
+	z.Zombie.Shove(g, from)
+}
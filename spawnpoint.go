@@ -5,7 +5,6 @@ package main
 
 import (
 	"math"
-	"math/rand"
 )
 
 // SpawnPoints is an array of SpawnPoint
@@ -34,7 +33,7 @@ type SpawnPoint struct {
 	PrevPosition   SpawnPosition
 	NextSpawn      int
 	CanSpawn       bool
-	ZombieType     ZombieType
+	Mix            SpawnMix
 }
 
 // NextPosition gives the offset of the next spawning to the center of the point
@@ -54,8 +53,15 @@ func (s *SpawnPoint) NextPosition() Coord {
 	}
 }
 
-// SpawnZombie spawns one zombie
-func (s *SpawnPoint) SpawnZombie(g *GameScreen) {
+// SpawnZombie spawns one zombie of the given archetype and sets when the
+// spawn point spawns again
+func (s *SpawnPoint) SpawnZombie(g *GameScreen, archetype *ZombieArchetype) {
+	s.Spawn(g, archetype)
+	s.NextSpawn = g.Director.SpawnInterval()
+}
+
+// Spawn spawns one zombie of the given archetype
+func (s *SpawnPoint) Spawn(g *GameScreen, archetype *ZombieArchetype) {
 	var np, nc Coord
 
 	// At least one of the 12 positions should be OK
@@ -67,41 +73,23 @@ func (s *SpawnPoint) SpawnZombie(g *GameScreen) {
 		}
 	}
 
-	var sprites *SpriteSheet
-	switch s.ZombieType {
-	case zombieNormal:
-		fallthrough
-	case zombieCrawler:
-		zs := rand.Intn(zombieVariants + 1)
-		if zs == zombieVariants {
-			// Crawler
-			sprites = g.Sprites[spriteZombieCrawler]
-			s.ZombieType = zombieCrawler
-		} else {
-			// Normal
-			sprites = g.ZombieSprites[zs]
-			s.ZombieType = zombieNormal
-		}
-	case zombieSprinter:
-		sprites = g.Sprites[spriteZombieSprinter]
-	case zombieBig:
-		sprites = g.Sprites[spriteZombieBig]
-	}
-
-	z := NewZombie(s, nc, s.ZombieType, sprites)
+	z := NewZombie(s, nc, archetype)
 
-	z.Target = g.Player.Object
 	g.Space.Add(z.Object)
 
-	if s.ZombieType == zombieBig {
-		boss := &Boss{Zombie: z}
+	if archetype.Boss != nil {
+		boss := NewBoss(z)
+		// Only one boss fight at a time, don't leave the old arena's walls behind
+		if g.Encounter != nil {
+			g.Encounter.End(g)
+		}
+		g.Encounter = NewEncounter(boss, s.Position)
 		g.Zombies = append(g.Zombies, boss)
 		s.Zombies = append(s.Zombies, boss)
 	} else {
 		g.Zombies = append(g.Zombies, z)
 		s.Zombies = append(s.Zombies, z)
 	}
-	s.NextSpawn = 180 + rand.Intn(180)
 }
 
 // Update updates the state of the spawn point
@@ -121,7 +109,7 @@ func (s *SpawnPoint) Update(g *GameScreen) {
 
 	// Spawn point is activated if the player is close enougn, but not too close
 	if playerDistance < spawnMaxDistance && playerDistance > spawnMinDistance {
-		if !s.InitialSpawned || (g.Tick%s.NextSpawn == 0 && len(s.Zombies) < s.InitialCount) {
+		if !s.InitialSpawned || (g.Tick%s.NextSpawn == 0 && len(s.Zombies) < g.Director.SpawnLimit(s.InitialCount)) {
 			s.CanSpawn = true
 		}
 	}
@@ -129,11 +117,12 @@ func (s *SpawnPoint) Update(g *GameScreen) {
 	if s.CanSpawn {
 		if !s.InitialSpawned {
 			for i := 0; i < s.InitialCount; i++ {
-				s.SpawnZombie(g)
+				s.SpawnZombie(g, s.Mix.Pick())
 			}
 			s.InitialSpawned = true
 		} else {
-			s.SpawnZombie(g)
+			// Only zombies spawned later on follow the pressure of the director
+			s.SpawnZombie(g, s.Mix.PickWith(g.Director.Weight))
 		}
 		s.CanSpawn = false
 	}
@@ -0,0 +1,56 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// How much extra spread (radians) the player's shots get at least while
+// moving in different ways
+var (
+	playerSpreadWalking   float64 = 0.08
+	playerSpreadStrafing  float64 = 0.12
+	playerSpreadSprinting float64 = 0.3
+)
+
+// playerSpreadRecovery is how much of the extra spread is kept each tick,
+// the player's aim steadies again while they stand still
+var playerSpreadRecovery float64 = 0.92
+
+// playerSpreadMax is the most extra spread (radians) moving and shooting
+// can add up to
+var playerSpreadMax float64 = 0.6
+
+// disturbAim makes the player's shots spread at least as much as the given
+// amount, e.g. while they move
+func (p *Player) disturbAim(amount float64) {
+	p.Bloom = math.Min(playerSpreadMax, math.Max(p.Bloom, amount))
+}
+
+// recoil makes the player's shots spread more each time they fire
+func (p *Player) recoil(amount float64) {
+	p.Bloom = math.Min(playerSpreadMax, p.Bloom+amount)
+}
+
+// steadyAim lets the player's aim recover a little
+func (p *Player) steadyAim() {
+	p.Bloom *= playerSpreadRecovery
+}
+
+// Spread returns the angle (radians) of the cone the player's shots fly in
+// right now, for the gun they are holding
+func (p *Player) Spread() float64 {
+	spread := p.Gun().Spread + p.Bloom
+	if p.Winded {
+		spread += playerWindedSpread
+	}
+	return spread * p.spreadFactor()
+}
+
+// shotAngle returns a random direction within the player's cone of fire
+func (p *Player) shotAngle() float64 {
+	return p.Angle + (rand.Float64()-0.5)*p.Spread()
+}
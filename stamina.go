@@ -0,0 +1,39 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+// How fast stamina is used up per tick of sprinting and comes back per tick
+// of walking or standing still, the player has 1 stamina when fully rested
+var (
+	playerStaminaDrain float64 = 0.005
+	playerStaminaRegen float64 = 0.003
+)
+
+// playerStaminaRecovery is how much stamina has to come back before the
+// player can sprint again after running out
+var playerStaminaRecovery float64 = 0.4
+
+// playerWindedSpread is how much wider (radians) the player's aim gets while
+// they are out of breath
+var playerWindedSpread float64 = 0.1
+
+// updateStamina uses up stamina while the player sprints and brings it back
+// while they don't, once it runs out they are winded until it partly recovers
+func (p *Player) updateStamina() {
+	if p.Sprinting {
+		p.Stamina -= playerStaminaDrain
+		if p.Stamina <= 0 {
+			p.Stamina = 0
+			p.Winded = true
+		}
+		return
+	}
+	p.Stamina += playerStaminaRegen
+	if p.Stamina > 1 {
+		p.Stamina = 1
+	}
+	if p.Winded && p.Stamina >= playerStaminaRecovery {
+		p.Winded = false
+	}
+}
@@ -14,4 +14,6 @@ type Stat struct {
 	CounterZombiesKilled int
 	CounterPlayerDied    int
 	CounterDogDied       int
+	CounterDogPetted     int
+	DogTrust             float64
 }
@@ -0,0 +1,98 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"github.com/solarlune/resolv"
+)
+
+// zombieTargetCommitment is how long (ticks) a zombie sticks with a target
+// before it considers switching to another one
+var zombieTargetCommitment int = 120
+
+// How much each reason to go after a target adds to its threat score, on top
+// of how close the target is
+var (
+	zombieNoiseWeight   float64 = 1   // For a noise right next to the zombie
+	zombieAggroWeight   float64 = 1   // For each recent hit taken from the player
+	zombieLoneDogWeight float64 = 0.5 // For a dog that is far from the player
+)
+
+// zombieLoneDogRadius is how far from the player the dog has to be to count as alone
+var zombieLoneDogRadius float64 = 160
+
+// zombieAggroMemory is how long (ticks) it takes a zombie to forget a hit
+const zombieAggroMemory = 600
+
+// zombieDogRangeFactor is how much further away zombies see the dog than the player
+const zombieDogRangeFactor = 1.2
+
+// threat scores how much the zombie wants to go after a target, 0 means it
+// doesn't notice the target at all
+func (z *Zombie) threat(g *GameScreen, target *resolv.Object, sightRange float64) float64 {
+	distance := CalcDistance(z.Object.X, z.Object.Y, target.X, target.Y)
+	score := math.Max(0, 1-distance/sightRange)
+	score += g.Noises.Loudness(*z.Position(), target) * zombieNoiseWeight
+	if score == 0 {
+		return 0 // it can neither see nor hear the target
+	}
+
+	switch target {
+	case g.Player.Object:
+		score += z.Aggro * zombieAggroWeight
+	case g.Dog.Object:
+		dogAlone := CalcDistance(g.Dog.Object.X, g.Dog.Object.Y, g.Player.Object.X, g.Player.Object.Y)
+		if dogAlone > zombieLoneDogRadius {
+			score += zombieLoneDogWeight
+		}
+	}
+	return score
+}
+
+// chooseTarget scores the player, the dog and anything the player threw to
+// lure zombies away and goes after the bigger threat, but sticks with its
+// current target for a while before switching
+func (z *Zombie) chooseTarget(g *GameScreen) {
+	z.Aggro = math.Max(0, z.Aggro-1/float64(zombieAggroMemory))
+
+	playerThreat := z.threat(g, g.Player.Object, z.Archetype.Range)
+	dogThreat := 0.0
+	if g.Dog.Mode != dogDead && z.canSeeDog(g) {
+		dogThreat = z.threat(g, g.Dog.Object, z.Archetype.Range*zombieDogRangeFactor)
+	}
+
+	lure, lureThreat := g.Throwables.lure(g, z)
+
+	// Keep going after the current target while committed, unless it's lost
+	if z.Commitment > 0 {
+		z.Commitment--
+		if (z.Target == g.Player.Object && playerThreat > 0) || (z.Target == g.Dog.Object && dogThreat > 0) || g.Throwables.luring(z.Target) {
+			return
+		}
+	}
+
+	var target *resolv.Object
+	switch {
+	case lureThreat > 0 && lureThreat >= playerThreat && lureThreat >= dogThreat:
+		target = lure
+	case playerThreat > 0 && playerThreat >= dogThreat:
+		target = g.Player.Object
+	case dogThreat > 0:
+		target = g.Dog.Object
+	}
+	if target != z.Target && target != nil {
+		z.Commitment = zombieTargetCommitment
+	}
+	z.Target = target
+}
+
+// provoke makes the zombie go after the player right away, e.g. when it was
+// shot, and remember it for a while
+func (z *Zombie) provoke(g *GameScreen) {
+	z.Aggro++
+	z.Target = g.Player.Object
+	z.Commitment = zombieTargetCommitment
+}
@@ -0,0 +1,307 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/solarlune/resolv"
+)
+
+// Kinds of things the player can throw
+const (
+	throwableFlare  = iota // Lights up an area and lures zombies to it while it burns
+	throwableBottle        // Smashes loudly and lures zombies that aren't after anything
+	howManyThrowables
+)
+
+// throwableNames are the names of the throwables shown in the HUD
+var throwableNames = [howManyThrowables]string{"flare", "bottle"}
+
+// How many of each throwable the player starts with
+var (
+	playerStartingFlares  int = 2
+	playerStartingBottles int = 3
+)
+
+// playerThrowRange is how far the player can throw something
+var playerThrowRange float64 = 160
+
+// How fast (distance per tick) and how high things fly when thrown
+const (
+	throwSpeed     = 3
+	throwArcHeight = 24
+)
+
+// throwWallMargin is how far in front of a wall something thrown at it lands
+const throwWallMargin = 4
+
+// flareTime is how long (ticks) a flare burns for
+var flareTime int = 480
+
+// flareRadius is how far a burning flare lights up around it
+var flareRadius float64 = 64
+
+// flareLureRadius is how far away zombies see a burning flare and go to it
+var flareLureRadius float64 = 200
+
+// bottleNoiseRadius is how far away zombies hear a bottle smash
+var bottleNoiseRadius float64 = 400
+
+// bottleLureTime is how long (ticks) zombies that heard a bottle smash keep
+// going to where it landed
+var bottleLureTime int = 240
+
+// throwableLureWeight is how much zombies want to go after a flare right
+// next to them or a bottle smashing, on top of the weight of the noise
+const throwableLureWeight = 2
+
+// Colours of the throwables
+var (
+	flareColour  = color.RGBA{0xff, 0x40, 0x20, 0xff}
+	flareLight   = color.RGBA{0xff, 0x80, 0x30, 0xff}
+	bottleColour = color.RGBA{0x40, 0x90, 0x50, 0xff}
+	shadowColour = color.RGBA{0x00, 0x00, 0x00, 0x60}
+)
+
+// flareGlow is the light around a burning flare, it is made the first time
+// it's needed since its size can be changed in the config
+var flareGlow *ebiten.Image
+
+// Throwable is something the player threw, it flies in an arc and lures
+// zombies away from the player and the dog once it lands
+type Throwable struct {
+	Kind     int            // What was thrown
+	Object   *resolv.Object // Where it is, zombies lured by it go after it
+	From     Coord          // Where it was thrown from
+	To       Coord          // Where it lands
+	Flight   int            // Ticks it has been in the air
+	Duration int            // Ticks it takes to land
+	Time     int            // Ticks left until it stops luring zombies after it landed
+}
+
+// Throwables is an array of Throwable
+type Throwables []*Throwable
+
+// handleThrowing lets the player switch between throwables with T and throw
+// the one they have ready with G
+func (g *GameScreen) handleThrowing() {
+	p := g.Player
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		p.Throwable = (p.Throwable + 1) % howManyThrowables
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyG) {
+		x, y := g.Camera.GetCursorCoords()
+		p.Throw(g, Coord{X: x, Y: y})
+	}
+}
+
+// Throw throws the throwable the player has ready towards the target, as far
+// as they can throw
+func (p *Player) Throw(g *GameScreen, target Coord) {
+	if p.Throwables[p.Throwable] < 1 || p.Staggered > 0 || p.Health <= 0 {
+		return
+	}
+	p.Throwables[p.Throwable]--
+
+	from := *p.Position()
+	distance := CalcDistance(from.X, from.Y, target.X, target.Y)
+	if distance > playerThrowRange {
+		target.X = from.X + (target.X-from.X)*playerThrowRange/distance
+		target.Y = from.Y + (target.Y-from.Y)*playerThrowRange/distance
+		distance = playerThrowRange
+	}
+
+	// Whatever is thrown drops down in front of the first wall in its way
+	angle := math.Atan2(target.Y-from.Y, target.X-from.X)
+	if hits := g.castRay(from, angle, distance, tagWall); len(hits) > 0 {
+		distance = math.Max(0, hits[0].Distance-throwWallMargin)
+		target.X = from.X + math.Cos(angle)*distance
+		target.Y = from.Y + math.Sin(angle)*distance
+	}
+
+	g.Throwables = append(g.Throwables, &Throwable{
+		Kind:     p.Throwable,
+		Object:   resolv.NewObject(from.X, from.Y, 1, 1),
+		From:     from,
+		To:       target,
+		Duration: int(math.Max(1, distance/throwSpeed)),
+	})
+}
+
+// Restock gives the player at least as many throwables as they started with
+func (p *Player) Restock() {
+	starting := [howManyThrowables]int{playerStartingFlares, playerStartingBottles}
+	for kind, amount := range starting {
+		if p.Throwables[kind] < amount {
+			p.Throwables[kind] = amount
+		}
+	}
+}
+
+// landed returns whether the throwable has hit the ground
+func (t *Throwable) landed() bool {
+	return t.Flight >= t.Duration
+}
+
+// Update moves the throwable through the air, and once it lands counts down
+// until it stops luring zombies
+func (t *Throwable) Update(g *GameScreen) {
+	if t.landed() {
+		t.Time--
+		return
+	}
+
+	t.Flight++
+	progress := float64(t.Flight) / float64(t.Duration)
+	t.Object.X = lerp(t.From.X, t.To.X, progress)
+	t.Object.Y = lerp(t.From.Y, t.To.Y, progress)
+	if !t.landed() {
+		return
+	}
+
+	switch t.Kind {
+	case throwableFlare:
+		t.Time = flareTime
+	case throwableBottle:
+		t.Time = bottleLureTime
+		g.MakeNoise(*t.Position(), bottleNoiseRadius, t.Object)
+		g.Sounds[soundHit].Play()
+	}
+}
+
+// Position returns where the throwable is on the ground
+func (t *Throwable) Position() *Coord {
+	return &Coord{X: t.Object.X, Y: t.Object.Y}
+}
+
+// lure scores how much the zombie wants to go to the throwable, a flare is
+// seen by any zombie nearby but a bottle is only followed by zombies that
+// weren't after anything else when they heard it
+func (t *Throwable) lure(g *GameScreen, z *Zombie) float64 {
+	if !t.landed() || t.Time <= 0 {
+		return 0
+	}
+	switch t.Kind {
+	case throwableFlare:
+		distance := CalcDistance(z.Object.X, z.Object.Y, t.Object.X, t.Object.Y)
+		return math.Max(0, 1-distance/flareLureRadius) * throwableLureWeight
+	case throwableBottle:
+		if z.Target == t.Object {
+			// Still lured until the bottle stops luring, long after the noise died down
+			return zombieNoiseWeight * throwableLureWeight
+		}
+		if z.Target != nil {
+			return 0
+		}
+		return g.Noises.Loudness(*z.Position(), t.Object) * zombieNoiseWeight * throwableLureWeight
+	}
+	return 0
+}
+
+// lure finds the throwable the zombie wants to go to the most and how much
+func (ts Throwables) lure(g *GameScreen, z *Zombie) (*resolv.Object, float64) {
+	var best *resolv.Object
+	bestScore := 0.0
+	for _, t := range ts {
+		if score := t.lure(g, z); score > bestScore {
+			best, bestScore = t.Object, score
+		}
+	}
+	return best, bestScore
+}
+
+// luring returns whether the object is a throwable which is still luring
+// zombies to it
+func (ts Throwables) luring(o *resolv.Object) bool {
+	for _, t := range ts {
+		if t.Object == o {
+			return t.landed() && t.Time > 0
+		}
+	}
+	return false
+}
+
+// Update moves the throwables and drops the ones that stopped luring zombies
+func (ts *Throwables) Update(g *GameScreen) {
+	kept := (*ts)[:0]
+	for _, t := range *ts {
+		t.Update(g)
+		if !t.landed() || t.Time > 0 {
+			kept = append(kept, t)
+		}
+	}
+	*ts = kept
+}
+
+// Draw draws the throwables, those in the air are drawn above their shadow
+func (ts Throwables) Draw(g *GameScreen) {
+	for _, t := range ts {
+		x, y := g.surfaceCoords(t.Object.X, t.Object.Y)
+		height := 0.0
+		if !t.landed() {
+			progress := float64(t.Flight) / float64(t.Duration)
+			height = 4 * throwArcHeight * progress * (1 - progress)
+			ebitenutil.DrawRect(g.Camera.Surface, x-2, y-1, 4, 2, shadowColour)
+		}
+
+		switch t.Kind {
+		case throwableFlare:
+			if t.landed() {
+				t.drawGlow(g, x, y)
+			}
+			ebitenutil.DrawRect(g.Camera.Surface, x-1, y-height-2, 2, 4, flareColour)
+		case throwableBottle:
+			if t.landed() {
+				// Broken glass on the ground
+				ebitenutil.DrawRect(g.Camera.Surface, x-2, y, 1, 1, bottleColour)
+				ebitenutil.DrawRect(g.Camera.Surface, x+1, y-1, 1, 1, bottleColour)
+				ebitenutil.DrawRect(g.Camera.Surface, x, y+1, 1, 1, bottleColour)
+				continue
+			}
+			ebitenutil.DrawRect(g.Camera.Surface, x-1, y-height-3, 2, 5, bottleColour)
+		}
+	}
+}
+
+// drawGlow lights up the area around a burning flare, flickering a bit and
+// fading out as it burns down
+func (t *Throwable) drawGlow(g *GameScreen, x, y float64) {
+	if flareGlow == nil {
+		flareGlow = newGlowImage(int(flareRadius), flareLight)
+	}
+	brightness := math.Min(1, float64(t.Time)/60) * (0.85 + 0.15*rand.Float64())
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x-flareRadius, y-flareRadius)
+	op.ColorM.Scale(1, 1, 1, brightness)
+	op.CompositeMode = ebiten.CompositeModeLighter
+	g.Camera.Surface.DrawImage(flareGlow, op)
+}
+
+// newGlowImage makes a circle of light which fades out towards its edge
+func newGlowImage(radius int, clr color.RGBA) *ebiten.Image {
+	img := image.NewRGBA(image.Rect(0, 0, radius*2, radius*2))
+	for y := 0; y < radius*2; y++ {
+		for x := 0; x < radius*2; x++ {
+			d := math.Hypot(float64(x-radius), float64(y-radius)) / float64(radius)
+			if d >= 1 {
+				continue
+			}
+			a := 0.5 * (1 - d) * (1 - d)
+			img.SetRGBA(x, y, color.RGBA{
+				R: uint8(float64(clr.R) * a),
+				G: uint8(float64(clr.G) * a),
+				B: uint8(float64(clr.B) * a),
+				A: uint8(255 * a),
+			})
+		}
+	}
+	return ebiten.NewImageFromImage(img)
+}
@@ -0,0 +1,90 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// dogStartingTrust is how much the dog trusts the player at the start, from 0 to 1
+var dogStartingTrust float64 = 0.5
+
+// How much the dog's trust changes
+var (
+	dogTrustCloseGain   float64 = 0.0002 // Per tick the player stays close
+	dogTrustAloneLoss   float64 = 0.0004 // Per tick the player leaves the dog alone
+	dogTrustProtectGain float64 = 0.02   // When the player shoots a zombie going after the dog
+	dogTrustPetGain     float64 = 0.1    // When the player pets the dog at a checkpoint
+	dogTrustHurtLoss    float64 = 0.1    // When a zombie reaches the dog
+)
+
+// dogAloneRadius is how far away the player has to be to leave the dog alone
+var dogAloneRadius float64 = 256
+
+// dogPetRadius is how close the player has to be to pet the dog
+const dogPetRadius = 32
+
+// dogMaxCalmTime is how long (ticks) the dog takes to calm down after the
+// zombies are gone when it doesn't trust the player at all
+var dogMaxCalmTime int = 180
+
+// updateTrust makes the dog trust the player more when they stay close and
+// less when they leave it alone
+func (d *Dog) updateTrust(g *GameScreen) {
+	distance := CalcDistance(d.Object.X, d.Object.Y, g.Player.Object.X, g.Player.Object.Y)
+	switch {
+	case distance <= waitingRadius:
+		d.changeTrust(dogTrustCloseGain)
+	case distance > dogAloneRadius:
+		d.changeTrust(-dogTrustAloneLoss)
+	}
+}
+
+// changeTrust changes the dog's trust, keeping it between 0 and 1
+func (d *Dog) changeTrust(amount float64) {
+	d.Trust = math.Max(0, math.Min(1, d.Trust+amount))
+}
+
+// waitingRadius is how far ahead of the player the dog goes, further the
+// more it trusts the player to keep up
+func (d *Dog) waitingRadius() float64 {
+	return waitingRadius * lerp(0.75, 1.25, d.Trust)
+}
+
+// calmTime is how long (ticks) the dog takes to calm down after the zombies
+// are gone, a dog that trusts the player gets over it sooner
+func (d *Dog) calmTime() int {
+	return int(float64(dogMaxCalmTime) * (1 - d.Trust))
+}
+
+// fleePlayerWeight is how much the dog cares about running to the player
+// when it flees, it doesn't run to a player it doesn't trust
+func (d *Dog) fleePlayerWeight() float64 {
+	return dogFleePlayerWeight * 2 * d.Trust
+}
+
+// handlePetting lets the player pet the dog once at each checkpoint
+func (g *GameScreen) handlePetting() {
+	if !inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		return
+	}
+	d := g.Dog
+	if d.Mode != dogNormal || CalcDistance(d.Object.X, d.Object.Y, g.Player.Object.X, g.Player.Object.Y) > dogPetRadius {
+		return
+	}
+	collision := g.Player.Object.Check(0, 0, tagCheckpoint)
+	if collision == nil {
+		return
+	}
+	if o := collision.Objects[0]; g.Player.Object.Overlaps(o) && d.PettedCheckpoint < o.Data.(int) {
+		d.PettedCheckpoint = o.Data.(int)
+		d.changeTrust(dogTrustPetGain)
+		d.Acknowledge = dogAcknowledgeTime
+		g.Stat.CounterDogPetted++
+		g.Sounds[soundDogBark].Play()
+	}
+}
@@ -0,0 +1,240 @@
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/solarlune/resolv"
+)
+
+// playerFriendlyFire is whether the player's bullets can hit the dog
+var playerFriendlyFire bool = false
+
+// playerFriendlyFireDamage is how much health the dog loses when it is shot
+var playerFriendlyFireDamage int = 25
+
+// Weapon describes one type of gun the player can use, loaded from
+// assets/weapons.json
+type Weapon struct {
+	Name       string        `json:"name"`       // Name of the weapon
+	Damage     int           `json:"damage"`     // How many hit points one bullet takes away
+	Range      float64       `json:"range"`      // How far the bullets fly
+	Spread     float64       `json:"spread"`     // Angle (radians) of the cone the bullets fly in
+	Pellets    int           `json:"pellets"`    // How many bullets one shot fires
+	FireRate   int           `json:"fireRate"`   // Ticks between shots
+	Clip       int           `json:"clip"`       // How many shots fit in the gun
+	Reserve    int           `json:"reserve"`    // How many spare rounds the player starts with
+	Pickup     int           `json:"pickup"`     // How many rounds an ammo pickup gives, defaults to a clip
+	ReloadTime int           `json:"reloadTime"` // Ticks reloading takes at least, on top of the animation
+	Pierce     int           `json:"pierce"`     // How many zombies one bullet goes through
+	Speed      float64       `json:"speed"`      // Distance the bullets fly per tick, 0 for hitting straight away
+	Recoil     float64       `json:"recoil"`     // Extra spread (radians) each shot adds until the player steadies their aim
+	Knockback  float64       `json:"knockback"`  // How fast a bullet pushes back a zombie it hits
+	Stagger    int           `json:"stagger"`    // Ticks a zombie that is hit can't move for
+	BulletName string        `json:"bullet"`     // Image of a bullet in the HUD
+	CasingName string        `json:"casing"`     // Image of a spent casing in the HUD
+	Bullet     *ebiten.Image `json:"-"`          // Loaded bullet image
+	Casing     *ebiten.Image `json:"-"`          // Loaded casing image
+}
+
+// Gun is a weapon the player carries along with the ammo for it
+type Gun struct {
+	*Weapon
+	Ammo    int // How many shots are left in the clip
+	Reserve int // How many spare rounds are left to reload with
+}
+
+// Load the weapon definitions file from the embedded FS along with the
+// images the HUD uses for their ammo
+func loadWeapons(name string) []*Weapon {
+	log.Printf("loading %s\n", name)
+
+	file, err := assets.Open(name)
+	if err != nil {
+		log.Fatalf("error opening file %s: %v\n", name, err)
+	}
+	defer file.Close()
+
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		log.Fatalf("error reading from file %s: %v\n", name, err)
+	}
+
+	var weapons []*Weapon
+	if err := json.Unmarshal(data, &weapons); err != nil {
+		log.Fatalf("error parsing file %s as weapon definitions: %v\n", name, err)
+	}
+	if len(weapons) == 0 {
+		log.Fatalf("no weapons defined in %s\n", name)
+	}
+
+	images := map[string]*ebiten.Image{}
+	imageNamed := func(name string) *ebiten.Image {
+		if _, ok := images[name]; !ok {
+			images[name] = loadImage("assets/sprites/" + name + ".png")
+		}
+		return images[name]
+	}
+
+	for _, w := range weapons {
+		if w.Clip < 1 {
+			log.Fatalf("weapon %s has no room for ammo\n", w.Name)
+		}
+		if w.Damage == 0 {
+			w.Damage = 1
+		}
+		if w.Pellets == 0 {
+			w.Pellets = 1
+		}
+		if w.Pierce == 0 {
+			w.Pierce = 1
+		}
+		if w.Pickup == 0 {
+			w.Pickup = w.Clip
+		}
+		w.Bullet = imageNamed(w.BulletName)
+		w.Casing = imageNamed(w.CasingName)
+	}
+	return weapons
+}
+
+// NewGuns gives the player each of the weapons, fully loaded
+func NewGuns(weapons []*Weapon) []*Gun {
+	guns := make([]*Gun, len(weapons))
+	for i, w := range weapons {
+		guns[i] = &Gun{Weapon: w, Ammo: w.Clip, Reserve: w.Reserve}
+	}
+	return guns
+}
+
+// Supply returns how much ammo is left for the gun compared to what the
+// player started with, from 0 to 1
+func (gun *Gun) Supply() float64 {
+	rounds := float64(gun.Ammo + gun.Reserve)
+	return math.Min(1, rounds/float64(gun.Clip+gun.Weapon.Reserve))
+}
+
+// CanReload returns whether the gun has room in the clip and spare rounds to fill it
+func (gun *Gun) CanReload() bool {
+	return gun.Ammo < gun.Clip && gun.Reserve > 0
+}
+
+// Reload fills up the clip from the spare rounds
+func (gun *Gun) Reload() {
+	rounds := gun.Clip - gun.Ammo
+	if rounds > gun.Reserve {
+		rounds = gun.Reserve
+	}
+	gun.Ammo += rounds
+	gun.Reserve -= rounds
+}
+
+// Rearm gives the gun a full clip and at least as many spare rounds as the
+// player started with
+func (gun *Gun) Rearm() {
+	gun.Ammo = gun.Clip
+	if gun.Reserve < gun.Weapon.Reserve {
+		gun.Reserve = gun.Weapon.Reserve
+	}
+}
+
+// Gun returns the gun the player is holding
+func (p *Player) Gun() *Gun {
+	return p.Guns[p.CurrentGun]
+}
+
+// GunNamed returns the player's gun with the weapon name, or the one they
+// are holding if they don't have one like that
+func (p *Player) GunNamed(name string) *Gun {
+	for _, gun := range p.Guns {
+		if gun.Name == name {
+			return gun
+		}
+	}
+	return p.Gun()
+}
+
+// SwitchGun makes the player hold another one of their guns, it can't be
+// done in the middle of shooting or reloading
+func (p *Player) SwitchGun(gun int) {
+	if gun < 0 || gun >= len(p.Guns) || gun == p.CurrentGun {
+		return
+	}
+	switch p.State {
+	case playerShooting, playerReload:
+		return
+	}
+	p.CurrentGun = gun
+	p.Cooldown = 0
+}
+
+// handleGunSwitching switches to the gun of the number key that was pressed
+func (g *GameScreen) handleGunSwitching() {
+	for i := range g.Player.Guns {
+		if inpututil.IsKeyJustPressed(ebiten.Key1 + ebiten.Key(i)) {
+			g.Player.SwitchGun(i)
+		}
+	}
+}
+
+// Shot is the path of one bullet, for effects
+type Shot struct {
+	From   Coord // Where the bullet was fired from
+	Impact Coord // Where it stopped, in something or at the end of its range
+	Hit    bool  // Whether it hit a zombie
+}
+
+// fireBullet fires one bullet straight to wherever it stops in the given
+// direction
+func (g *GameScreen) fireBullet(gun *Gun, angle float64) Shot {
+	shot := Shot{From: *g.Player.Position()}
+	pierced := 0
+	distance, _, hit := g.bulletTravel(gun, shot.From, angle, g.Player.Range(), &pierced, map[*resolv.Object]bool{})
+	shot.Impact = Coord{
+		X: shot.From.X + math.Cos(angle)*distance,
+		Y: shot.From.Y + math.Sin(angle)*distance,
+	}
+	shot.Hit = hit
+	return shot
+}
+
+// bulletTravel moves a bullet along a stretch of its path, it hits the
+// zombies in the way from nearest to furthest until it has gone through as
+// many as the gun pierces, or stops at a wall. The dog stops it too when
+// friendly fire is on. Zombies that were already hit are skipped. It returns
+// how far the bullet got, whether it stopped and whether it hit a zombie.
+func (g *GameScreen) bulletTravel(gun *Gun, from Coord, angle, length float64, pierced *int, hit map[*resolv.Object]bool) (float64, bool, bool) {
+	hitZombie := false
+	for _, h := range g.castRay(from, angle, length, tagMob, tagWall, tagDog) {
+		switch {
+		case h.Object.HasTags(tagWall):
+			return h.Distance, true, hitZombie
+		case h.Object.HasTags(tagDog):
+			if !playerFriendlyFire {
+				continue
+			}
+			g.Dog.Hurt(g, playerFriendlyFireDamage)
+			return h.Distance, true, hitZombie
+		default:
+			if hit[h.Object] {
+				continue
+			}
+			hit[h.Object] = true
+			z := h.Object.Data.(Zombielike)
+			z.Hit(g, gun.Damage)
+			z.Knock(g, Coord{X: math.Cos(angle) * gun.Knockback, Y: math.Sin(angle) * gun.Knockback}, gun.Stagger)
+			hitZombie = true
+			if *pierced++; *pierced >= gun.Pierce {
+				return h.Distance, true, hitZombie
+			}
+		}
+	}
+	return length, false, hitZombie
+}
@@ -69,6 +69,8 @@ func (s *WinScreen) Draw(screen *ebiten.Image) {
 	statText = statText + fmt.Sprintf("Rover died %d times\n", s.Stat.CounterDogDied)
 	statText = statText + fmt.Sprintf("You fired %d bullets\n", s.Stat.CounterBulletsFired)
 	statText = statText + fmt.Sprintf("You killed %d zombies\n", s.Stat.CounterZombiesKilled)
+	statText = statText + fmt.Sprintf("You petted Rover %d times\n", s.Stat.CounterDogPetted)
+	statText = statText + fmt.Sprintf("Rover trusts you %d%%\n", int(s.Stat.DogTrust*100))
 	s.statRenderer.Renderer.SetTarget(screen)
 	s.statRenderer.Renderer.Draw(statText, screen.Bounds().Dx()/2, screen.Bounds().Dy()/5*3)
 }
@@ -19,35 +19,39 @@ func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
-// zombieSpeed is the distance the zombie moves per update cycle
-var zombieSpeed float64 = 0.4
+// zombieRange is how far away the zombie sees something to attack, unless
+// its archetype says otherwise
+var zombieRange float64 = 220
 
-// zombieCrawlerSpeed is the distance the crawler zombie moves per update cycle
-var zombieCrawlerSpeed float64 = 0.2
+// zombieAttackRange is how close the zombie has to be to its target to attack
+var zombieAttackRange float64 = 14
 
-// zombieSprinterSpeed is the distance the sprinter zombie moves per update cycle
-var zombieSprinterSpeed float64 = 1.2
+// zombieAttackWindup is how long (ticks) the zombie winds up before an attack lands
+var zombieAttackWindup int = 30
 
-// zombieRange is how far away the zombie sees something to attack
-var zombieRange float64 = 220
+// zombieAttackSightTime is how long (ticks) the zombie has to have been on
+// screen before its attacks can hurt the player, so nobody gets hit by a
+// zombie that came in from off screen before they could see it
+var zombieAttackSightTime int = 30
 
-// Types of zombies
-type ZombieType uint8
+// zombieDamage is how much health an attack takes away, unless its archetype
+// says otherwise
+var zombieDamage int = 25
 
-const (
-	zombieNormal ZombieType = iota
-	zombieCrawler
-	zombieSprinter
-	zombieBig
-)
+// zombieKnockbackFriction is how much of the knockback speed is kept each tick
+const zombieKnockbackFriction = 0.8
+
+// zombieAmmoDropChance is the chance of a zombie leaving ammo behind when it dies
+var zombieAmmoDropChance float64 = 0.1
 
 // Zombielike is anything that behaves like a zombie (e.g. attacks things and dies)
 type Zombielike interface {
 	Update(*GameScreen) error
 	Draw(*GameScreen)
-	Hit(*GameScreen)
+	Hit(*GameScreen, int)
+	Knock(*GameScreen, Coord, int)
+	Shove(*GameScreen, Coord)
 	Die(*GameScreen)
-	Type() ZombieType
 	Remove()
 	Position() *Coord
 }
@@ -81,27 +85,10 @@ func (zs *Zombies) Update(g *GameScreen) {
 	}
 }
 
-func NewZombie(spawnpoint *SpawnPoint, position Coord, zombieType ZombieType, sprites *SpriteSheet) *Zombie {
-	// the head and shoulders are about 3px from the middle
-	const collisionBoxSize float64 = 6
-
-	var speed float64
-	var hitToDie int
-
-	switch zombieType {
-	case zombieNormal:
-		speed = zombieSpeed
-		hitToDie = 1 + rand.Intn(2)
-	case zombieCrawler:
-		speed = zombieCrawlerSpeed
-		hitToDie = 1 + rand.Intn(2)
-	case zombieSprinter:
-		speed = zombieSprinterSpeed
-		hitToDie = 1
-	case zombieBig:
-		speed = zombieSpeed
-		hitToDie = 10
-	}
+// NewZombie creates a zombie of the given archetype, with its speed, health
+// and looks randomly picked from the ranges the archetype allows
+func NewZombie(spawnpoint *SpawnPoint, position Coord, archetype *ZombieArchetype) *Zombie {
+	sprites := archetype.RandomSprite()
 
 	dimensions := sprites.Sprite[0].Position
 	object := resolv.NewObject(
@@ -111,18 +98,19 @@ func NewZombie(spawnpoint *SpawnPoint, position Coord, zombieType ZombieType, sp
 	)
 	object.SetShape(resolv.NewRectangle(
 		0, 0, // origin
-		collisionBoxSize, collisionBoxSize,
+		archetype.CollisionBox, archetype.CollisionBox,
 	))
 	object.Shape.(*resolv.ConvexPolygon).RecenterPoints()
 
 	z := &Zombie{
-		Object:     object,
-		Angle:      0,
-		Sprite:     sprites,
-		Speed:      speed * (1 + rand.Float64()),
-		HitToDie:   hitToDie,
-		ZombieType: zombieType,
-		TempSpeed:  1,
+		Object:    object,
+		Angle:     0,
+		Sprite:    sprites,
+		Speed:     archetype.Speed.Random(),
+		HitToDie:  archetype.HitPoints.Random(),
+		Archetype: archetype,
+		TempSpeed: 1,
+		Flank:     (rand.Float64()*2 - 1) * zombieMaxFlank,
 	}
 	z.Object.Data = z
 	z.SpawnPoint = spawnpoint
@@ -142,23 +130,36 @@ const (
 	zombieIdle    = iota // Doesn't have any target to attack
 	zombieWalking        // Walking in some direction
 	zombieHit            // Hit by a shot, but not deadly
+	zombieStunned        // Shoved or staggered by a hit and can't move for a while
 	zombieDeath          // Plays the death animation
 	zombieDead           // Marked as dead, will be removed on next Update
 )
 
+// Maps zombie states to animation frames, stunned zombies look like they
+// were hit
+var zombieStateToFrame = [6]int{0, 1, 2, 2, 3, 4}
+
 // Zombie is a monster that's trying to eat the player character
 type Zombie struct {
-	Object     *resolv.Object // Used for collision detection with other objects
-	Angle      float64        // The angle the zombies is facing at
-	Frame      int            // The current animation frame
-	State      int            // The current animation state
-	Sprite     *SpriteSheet   // Used for zombie animations
-	Speed      float64        // The speed this zombie walks at
-	TempSpeed  float64        // Temporary speed multiplier
-	Target     *resolv.Object // Target object (player or dog)
-	HitToDie   int            // Number of hits needed to die
-	ZombieType ZombieType     // Type of the zombie
-	SpawnPoint *SpawnPoint    // Reference for the SpawnPoint where the zombie was spawned
+	Object     *resolv.Object   // Used for collision detection with other objects
+	Angle      float64          // The angle the zombies is facing at
+	Frame      int              // The current animation frame
+	State      int              // The current animation state
+	Sprite     *SpriteSheet     // Used for zombie animations
+	Speed      float64          // The speed this zombie walks at
+	TempSpeed  float64          // Temporary speed multiplier
+	Target     *resolv.Object   // Target object (player or dog)
+	HitToDie   int              // Number of hits needed to die
+	Archetype  *ZombieArchetype // Type of the zombie
+	SpawnPoint *SpawnPoint      // Reference for the SpawnPoint where the zombie was spawned
+	Velocity   Coord            // Current direction of movement, used for flocking
+	Flank      float64          // Angle offset this zombie uses to surround its target
+	Windup     int              // Ticks spent winding up the current attack
+	Aggro      float64          // How provoked the zombie is by recent hits from the player
+	Commitment int              // Ticks left before the zombie considers switching target
+	Stunned    int              // Ticks left until the zombie recovers from a shove or a hit
+	Knockback  Coord            // Velocity the zombie is being pushed with after a shove or a hit
+	Seen       int              // Ticks the zombie has been on screen for
 }
 
 // Remove the zombie from the game's list of zombies and from the spawn point's
@@ -178,50 +179,43 @@ func (z *Zombie) Position() *Coord {
 	}
 }
 
-// Type returns what type of zombie this is
-func (z *Zombie) Type() ZombieType {
-	return z.ZombieType
-}
-
 // Update updates the state of the zombie
 func (z *Zombie) Update(g *GameScreen) error {
 	if z.State == zombieDead {
 		return errors.New("Zombie died")
 	}
 
-	if z.State == zombieIdle || z.State == zombieWalking {
-		playerDistance, _, _ := CalcObjectDistance(z.Position(), g.Player.Position())
-		dogDistance, _, _ := CalcObjectDistance(z.Position(), g.Dog.Position())
-
-		zShouldWalk := false
+	if g.isOnScreen(z.Object.X, z.Object.Y) {
+		z.Seen++
+	} else {
+		z.Seen = 0
+	}
 
-		if playerDistance < zombieRange {
-			z.Target = g.Player.Object
-			zShouldWalk = true
-		} else if dogDistance < zombieRange*1.2 {
-			z.Target = g.Dog.Object
-			zShouldWalk = true
-		}
+	if z.State == zombieIdle || z.State == zombieWalking {
+		z.chooseTarget(g)
 
-		if zShouldWalk {
+		if z.Target != nil {
 			if z.State == zombieIdle {
 				// Zombie detects target
-				if z.ZombieType == zombieNormal || z.ZombieType == zombieCrawler {
-					g.Sounds[soundZombieGrowl].Play()
-				} else if z.ZombieType == zombieSprinter {
-					g.Sounds[soundZombieScream].Play()
-				} else {
-					g.Sounds[soundBigZombieSound].Play()
-				}
+				z.Archetype.PlaySound(zombieSoundDetect)
+			}
+			if z.inAttackRange(z.Target) {
+				z.attack(g)
+			} else {
+				z.Windup = 0
+				z.walk(g)
 			}
-			z.walk()
 		} else {
+			z.Windup = 0
 			z.State = zombieIdle
 		}
+	} else if z.State == zombieStunned || z.State == zombieHit {
+		z.updateStun()
 	}
 
-	z.Frame = Animate(z.Frame, g.Tick, z.Sprite.Meta.FrameTags[z.State])
-	if z.Frame == z.Sprite.Meta.FrameTags[z.State].To {
+	tag := z.Sprite.Meta.FrameTags[zombieStateToFrame[z.State]]
+	z.Frame = Animate(z.Frame, g.Tick, tag)
+	if z.Frame == tag.To {
 		z.animationBasedStateChanges(g)
 	}
 
@@ -230,25 +224,44 @@ func (z *Zombie) Update(g *GameScreen) error {
 	return nil
 }
 
-func (z *Zombie) walk() {
+func (z *Zombie) walk(g *GameScreen) {
 	// Zombies rotate towards their target
 	adjacent := z.Target.X - z.Object.X
 	opposite := z.Target.Y - z.Object.Y
 	z.Angle = math.Atan2(opposite, adjacent)
 
-	// Zombie movement logic
-	// TODO: this could be simplified using maths
-	if z.Object.X < z.Target.X {
-		z.MoveRight()
-	}
-	if z.Object.X > z.Target.X {
-		z.MoveLeft()
-	}
-	if z.Object.Y < z.Target.Y {
-		z.MoveDown()
+	// Smoothly turn towards the steering direction so the horde flows
+	dir := z.steer(g)
+	z.Velocity.X += (dir.X - z.Velocity.X) * zombieSteeringSmoothing
+	z.Velocity.Y += (dir.Y - z.Velocity.Y) * zombieSteeringSmoothing
+
+	speed := z.Speed * z.TempSpeed
+	z.move(z.Velocity.X*speed, z.Velocity.Y*speed)
+}
+
+// inAttackRange returns whether the object is close enough to be attacked
+func (z *Zombie) inAttackRange(o *resolv.Object) bool {
+	return CalcDistance(z.Object.X, z.Object.Y, o.X, o.Y) < zombieAttackRange
+}
+
+// attack winds up an attack on the target and hurts it when it lands, but
+// the player is only hurt once the zombie has been on screen for a while
+func (z *Zombie) attack(g *GameScreen) {
+	z.State = zombieWalking
+	z.Angle = math.Atan2(z.Target.Y-z.Object.Y, z.Target.X-z.Object.X)
+
+	z.Windup++
+	if z.Windup < zombieAttackWindup {
+		return
 	}
-	if z.Object.Y > z.Target.Y {
-		z.MoveUp()
+	z.Windup = 0
+	switch z.Target {
+	case g.Player.Object:
+		if z.Seen >= zombieAttackSightTime {
+			g.Player.Hurt(g, z.Archetype.Damage, z.Position())
+		}
+	case g.Dog.Object:
+		g.Dog.Hurt(g, z.Archetype.Damage)
 	}
 }
 
@@ -257,38 +270,21 @@ func (z *Zombie) animationBasedStateChanges(g *GameScreen) {
 	switch z.State {
 	case zombieHit:
 		z.State = zombieWalking
+		if z.Stunned > 0 {
+			z.State = zombieStunned // still staggering from the hit
+		}
 	case zombieDeath:
 		z.State = zombieDead
 	}
 }
 
-// MoveUp moves the zombie upwards
-func (z *Zombie) MoveUp() {
-	z.move(0, -z.Speed*z.TempSpeed)
-}
-
-// MoveDown moves the zombie downwards
-func (z *Zombie) MoveDown() {
-	z.move(0, z.Speed*z.TempSpeed)
-}
-
-// MoveLeft moves the zombie left
-func (z *Zombie) MoveLeft() {
-	z.move(-z.Speed*z.TempSpeed, 0)
-}
-
-// MoveRight moves the zombie right
-func (z *Zombie) MoveRight() {
-	z.move(z.Speed*z.TempSpeed, 0)
-}
-
-// Move the Zombie by the given vector if it is possible to do so
+// Move the Zombie by the given vector if it is possible to do so, sliding
+// along walls instead of stopping dead. Other zombies are kept apart by the
+// separation steering rather than by blocking movement.
 func (z *Zombie) move(dx, dy float64) {
 	z.State = zombieWalking
-	if collision := z.Object.Check(dx, dy, tagMob, tagWall); collision == nil {
-		z.Object.X += dx
-		z.Object.Y += dy
-	}
+	z.slide(dx, dy)
+
 	// Collision detection and response between sand trap and zombie
 	z.TempSpeed = 1
 	if collision := z.Object.Check(0, 0, tagSandTrap); collision != nil {
@@ -300,6 +296,30 @@ func (z *Zombie) move(dx, dy float64) {
 	}
 }
 
+// Slide the Zombie by the given vector, stopping along each axis where it
+// would run into a wall
+func (z *Zombie) slide(dx, dy float64) {
+	if collision := z.Object.Check(dx, 0, tagWall); collision != nil {
+		for _, o := range collision.Objects {
+			if z.Object.Shape.Intersection(dx, 0, o.Shape) != nil {
+				dx = 0
+				break
+			}
+		}
+	}
+	z.Object.X += dx
+	if collision := z.Object.Check(0, dy, tagWall); collision != nil {
+		for _, o := range collision.Objects {
+			if z.Object.Shape.Intersection(0, dy, o.Shape) != nil {
+				dy = 0
+				break
+			}
+		}
+	}
+	z.Object.Y += dy
+	z.Object.Shape.SetPosition(z.Object.X, z.Object.Y)
+}
+
 // Draw draws the Zombie to the screen
 func (z *Zombie) Draw(g *GameScreen) {
 	// -2, // the centre of the zombie's head is 2px up from the middle
@@ -316,6 +336,12 @@ func (z *Zombie) Draw(g *GameScreen) {
 	)
 	op.GeoM.Rotate(z.Angle + math.Pi/2)
 
+	// Turn redder while winding up an attack so it can be seen coming
+	if z.Windup > 0 {
+		fade := 1 - 0.6*float64(z.Windup)/float64(zombieAttackWindup)
+		op.ColorM.Scale(1, fade, fade, 1)
+	}
+
 	g.Camera.Surface.DrawImage(
 		s.Image.SubImage(image.Rect(
 			frame.Position.X,
@@ -332,23 +358,73 @@ func (z *Zombie) Draw(g *GameScreen) {
 
 }
 
-// Hit changes zombie state and updates game data in response to it getting shot
-func (z *Zombie) Hit(g *GameScreen) {
+// Hit changes zombie state and updates game data in response to it getting
+// shot, taking away as many hit points as the damage
+func (z *Zombie) Hit(g *GameScreen, damage int) {
 	g.Stat.CounterZombiesHit++
-	z.State = zombieHit
-	z.HitToDie--
-	if z.HitToDie == 0 {
+	if z.Target == g.Dog.Object {
+		// The dog sees the player protect it
+		g.Dog.changeTrust(dogTrustProtectGain)
+	}
+	z.provoke(g)
+	if z.State != zombieStunned {
+		z.State = zombieHit
+	}
+	z.HitToDie -= damage
+	if z.HitToDie <= 0 {
 		z.Die(g)
 	} else {
 		g.Sounds[soundHit].Play()
-		g.Sounds[soundZombieGrowl].Play()
+		z.Archetype.PlaySound(zombieSoundHurt)
+	}
+}
+
+// Knock pushes the zombie along the way a bullet was flying and staggers it
+// for a moment, unless it is already dying
+func (z *Zombie) Knock(g *GameScreen, push Coord, stagger int) {
+	if z.State == zombieDeath || z.State == zombieDead {
+		return
+	}
+	z.knockBack(push, stagger)
+}
+
+// knockBack pushes the zombie and stops it from moving for the given ticks,
+// heavier zombies are pushed less and get over it sooner
+func (z *Zombie) knockBack(push Coord, ticks int) {
+	mass := z.Archetype.Mass
+	z.Knockback.X += push.X / mass
+	z.Knockback.Y += push.Y / mass
+	if stun := int(float64(ticks) / mass); stun > z.Stunned {
+		z.Stunned = stun
+	}
+	z.Windup = 0
+}
+
+// updateStun slides the zombie along with the knockback, stopping at walls,
+// until it comes to its senses again
+func (z *Zombie) updateStun() {
+	z.slide(z.Knockback.X, z.Knockback.Y)
+	z.Knockback.X *= zombieKnockbackFriction
+	z.Knockback.Y *= zombieKnockbackFriction
+
+	if z.Stunned > 0 {
+		z.Stunned--
+	}
+	if z.Stunned == 0 {
+		z.Knockback = Coord{}
+		if z.State == zombieStunned {
+			z.State = zombieWalking
+		}
 	}
 }
 
 // Die changes zombie state and updates game data in case of a deadly shot
 func (z *Zombie) Die(g *GameScreen) {
 	g.Stat.CounterZombiesKilled++
-	g.Sounds[soundZombieDeath].Play()
+	if rand.Float64() < zombieAmmoDropChance {
+		g.DropPickup(pickupAmmo, *z.Position())
+	}
+	z.Archetype.PlaySound(zombieSoundDeath)
 	z.Remove()
 	z.State = zombieDeath
 }